@@ -0,0 +1,15 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/L11D/avito-review-assign-service/internal/app"
+)
+
+func main() {
+	if err := app.RunOrgImport(os.Args[1:]); err != nil {
+		slog.Error("Org chart import failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
+func TestMetrics(t *testing.T) {
+	team := dto.TeamDTO{
+		Name: "MetricsTeam",
+		Members: []dto.TeamMemberDTO{
+			{ID: "metrics_u1", Username: "Alice", IsActive: GetBoolPtr(true)},
+			{ID: "metrics_u2", Username: "Bob", IsActive: GetBoolPtr(true)},
+		},
+	}
+
+	resp, _ := MakeJSONRequest(t, "POST", os.Getenv("API_URL")+"/team/add", team)
+	AssertStatusCode(t, resp, 201)
+
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "metrics_pr1",
+		Name:     "metrics",
+		AuthorID: "metrics_u1",
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", os.Getenv("API_URL")+"/pullRequest/create", createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", os.Getenv("API_URL")+"/pullRequest/merge",
+		dto.PullRequestMergeDTO{ID: createPRDTO.ID})
+	AssertStatusCode(t, resp, 200)
+
+	resp, body := MakeQueryRequest(t, "GET", os.Getenv("API_URL")+"/metrics", nil)
+	AssertStatusCode(t, resp, 200)
+
+	expected := []string{
+		`review_assign_http_requests_total{method="POST",route="/pullRequest/create",status="201"}`,
+		`review_assign_http_request_duration_seconds_bucket{method="POST",route="/team/add"`,
+		`review_assign_db_query_duration_seconds_bucket{query="pullRequestRepo.Save"`,
+		`review_assign_db_transaction_duration_seconds_count{status="commit"}`,
+		`review_assign_pull_requests_created_total`,
+		`review_assign_pull_requests_merged_total`,
+		`review_assign_reviewers_per_pull_request_bucket`,
+		`review_assign_reviewer_reassignments_total`,
+	}
+
+	for _, metric := range expected {
+		if !strings.Contains(string(body), metric) {
+			t.Fatalf("expected metric %s in the /metrics output", metric)
+		}
+	}
+}
@@ -422,3 +422,189 @@ func TestReassignPullRequest_NotAssigned(t *testing.T) {
 		t.Fatalf("expected error code NOT_ASSIGNED, got %s", errorMessage.Error.Code)
 	}
 }
+
+func TestDeclinePullRequest(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "declin1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember2 := dto.TeamMemberDTO{
+		ID:       "declin2",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember3 := dto.TeamMemberDTO{
+		ID:       "declin3",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember4 := dto.TeamMemberDTO{
+		ID:       "declin4",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamDecline1",
+		Members: []dto.TeamMemberDTO{teamMember1, teamMember2, teamMember3, teamMember4},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "declinePR1",
+		Name:     "pull req",
+		AuthorID: teamMember1.ID,
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	var fetchedFullPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &fetchedFullPR)
+	decliningReviewer := fetchedFullPR.PullRequest.Reviewers[0]
+
+	url = os.Getenv("API_URL") + "/pullRequest/decline"
+	declineDTO := dto.PullRequestDeclineDTO{
+		PullRequestID: createPRDTO.ID,
+		ReviewerID:    decliningReviewer,
+		Reason:        "No context on this service",
+	}
+
+	resp, body = MakeJSONRequest(t, "POST", url, declineDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var declineResult dto.PullRequestDeclineResultDTO
+	ParseJSONResponse(t, body, &declineResult)
+
+	if declineResult.ReplacedBy == "" ||
+		declineResult.ReplacedBy == decliningReviewer ||
+		len(declineResult.PullRequest.Reviewers) != 2 {
+		t.Fatalf("Pull Request decline did not work as expected")
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/reassign"
+	reassignPRDTO := dto.PullRequestReassignDTO{
+		PullRequestID: createPRDTO.ID,
+		OldReviewerID: declineResult.ReplacedBy,
+	}
+
+	resp, body = MakeJSONRequest(t, "POST", url, reassignPRDTO)
+	AssertStatusCode(t, resp, 409)
+
+	var errorMessage dto.FullErrorDTO
+	ParseJSONResponse(t, body, &errorMessage)
+
+	if errorMessage.Error.Code != "NO_CANDIDATE" {
+		t.Fatalf("expected error code NO_CANDIDATE, got %s", errorMessage.Error.Code)
+	}
+}
+
+func TestDeclinePullRequest_NotAssigned(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "declin11",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamDecline2",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "declinePR2",
+		Name:     "pull req",
+		AuthorID: teamMember1.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/decline"
+	declineDTO := dto.PullRequestDeclineDTO{
+		PullRequestID: createPRDTO.ID,
+		ReviewerID:    teamMember1.ID,
+		Reason:        "Not mine",
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, declineDTO)
+	AssertStatusCode(t, resp, 409)
+}
+
+func TestDeclinePullRequest_Twice(t *testing.T) {
+	lead := dto.TeamMemberDTO{ID: "declin3l", Username: "Bob", IsActive: GetBoolPtr(true), Role: dto.TeamRoleLead}
+	author := dto.TeamMemberDTO{ID: "declin3u1", Username: "Alice", IsActive: GetBoolPtr(true)}
+	member1 := dto.TeamMemberDTO{ID: "declin3u2", Username: "Carol", IsActive: GetBoolPtr(true)}
+	member2 := dto.TeamMemberDTO{ID: "declin3u3", Username: "Dave", IsActive: GetBoolPtr(true)}
+
+	team := dto.TeamDTO{
+		Name:    "TeamDecline3",
+		Members: []dto.TeamMemberDTO{lead, author, member1, member2},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "declinePR3",
+		Name:     "pull req",
+		AuthorID: author.ID,
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	var createdPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &createdPR)
+	decliningReviewer := createdPR.PullRequest.Reviewers[0]
+
+	url = os.Getenv("API_URL") + "/pullRequest/decline"
+	declineDTO := dto.PullRequestDeclineDTO{
+		PullRequestID: createPRDTO.ID,
+		ReviewerID:    decliningReviewer,
+		Reason:        "No context on this service",
+	}
+
+	resp, body = MakeJSONRequest(t, "POST", url, declineDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var declineResult dto.PullRequestDeclineResultDTO
+	ParseJSONResponse(t, body, &declineResult)
+
+	url = os.Getenv("API_URL") + "/pullRequest/overrideReviewer"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestOverrideReviewerDTO{
+		PullRequestID: createPRDTO.ID,
+		LeadID:        lead.ID,
+		OldReviewerID: declineResult.PullRequest.Reviewers[0],
+		NewReviewerID: decliningReviewer,
+	})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/pullRequest/decline"
+	resp, body = MakeJSONRequest(t, "POST", url, declineDTO)
+	AssertStatusCode(t, resp, 409)
+
+	var errorMessage dto.FullErrorDTO
+	ParseJSONResponse(t, body, &errorMessage)
+
+	if errorMessage.Error.Code != "ALREADY_DECLINED" {
+		t.Fatalf("expected error code ALREADY_DECLINED, got %s", errorMessage.Error.Code)
+	}
+}
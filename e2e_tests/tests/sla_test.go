@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
+func TestSetTeamSLA(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "sla1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamSla1",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/setSla"
+	policy := dto.TeamSLAPolicyDTO{
+		TeamName:         team.Name,
+		ReviewSLA:        "48h",
+		Action:           dto.SLAActionEscalate,
+		BackupReviewerID: &teamMember1.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, policy)
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/team/getSla"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"name": team.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var fetchedPolicy dto.TeamSLAPolicyDTO
+	ParseJSONResponse(t, body, &fetchedPolicy)
+
+	if fetchedPolicy.TeamName != team.Name ||
+		fetchedPolicy.ReviewSLA != "48h0m0s" ||
+		fetchedPolicy.Action != dto.SLAActionEscalate ||
+		fetchedPolicy.BackupReviewerID == nil ||
+		*fetchedPolicy.BackupReviewerID != teamMember1.ID {
+		t.Fatalf("SLA policy does not match expected values: %s", string(body))
+	}
+}
+
+func TestSetTeamSLA_EscalateWithoutBackup(t *testing.T) {
+	url := os.Getenv("API_URL") + "/team/setSla"
+	policy := dto.TeamSLAPolicyDTO{
+		TeamName:  "TeamSla1",
+		ReviewSLA: "48h",
+		Action:    dto.SLAActionEscalate,
+	}
+
+	resp, _ := MakeJSONRequest(t, "POST", url, policy)
+	AssertStatusCode(t, resp, 400)
+}
+
+func TestGetOverdueReviews(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "sla2u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember2 := dto.TeamMemberDTO{
+		ID:       "sla2u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamSla2",
+		Members: []dto.TeamMemberDTO{teamMember1, teamMember2},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/setSla"
+	policy := dto.TeamSLAPolicyDTO{
+		TeamName:  team.Name,
+		ReviewSLA: "1s",
+		Action:    dto.SLAActionReassign,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, policy)
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "slaPR2",
+		Name:     "pull req",
+		AuthorID: teamMember1.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	time.Sleep(2 * time.Second)
+
+	url = os.Getenv("API_URL") + "/pullRequest/overdue"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"team_name": team.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var overdue dto.OverdueReviewsDTO
+	ParseJSONResponse(t, body, &overdue)
+
+	if len(overdue.OverdueReviews) != 1 ||
+		overdue.OverdueReviews[0].PullRequestID != createPRDTO.ID ||
+		overdue.OverdueReviews[0].ReviewerID != teamMember2.ID {
+		t.Fatalf("Overdue reviews do not match expected values: %s", string(body))
+	}
+}
+
+func TestGetOverdueReviews_SkipsSubmittedVerdicts(t *testing.T) {
+	author := dto.TeamMemberDTO{
+		ID:       "sla3u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer1 := dto.TeamMemberDTO{
+		ID:       "sla3u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer2 := dto.TeamMemberDTO{
+		ID:       "sla3u3",
+		Username: "Carol",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamSla3",
+		Members: []dto.TeamMemberDTO{author, reviewer1, reviewer2},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/setSla"
+	policy := dto.TeamSLAPolicyDTO{
+		TeamName:  team.Name,
+		ReviewSLA: "1s",
+		Action:    dto.SLAActionReassign,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, policy)
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "slaPR3",
+		Name:     "pull req",
+		AuthorID: author.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/submitVerdict"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestVerdictDTO{
+		PullRequestID: createPRDTO.ID,
+		ReviewerID:    reviewer1.ID,
+		Verdict:       dto.VerdictApproved,
+	})
+	AssertStatusCode(t, resp, 200)
+
+	time.Sleep(2 * time.Second)
+
+	url = os.Getenv("API_URL") + "/pullRequest/overdue"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"team_name": team.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var overdue dto.OverdueReviewsDTO
+	ParseJSONResponse(t, body, &overdue)
+
+	if len(overdue.OverdueReviews) != 1 ||
+		overdue.OverdueReviews[0].ReviewerID != reviewer2.ID {
+		t.Fatalf("Overdue reviews do not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/users/reviewQueue"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer1.ID, "include_reviewed": "true"})
+	AssertStatusCode(t, resp, 200)
+
+	var queue dto.UserReviewQueueDTO
+	ParseJSONResponse(t, body, &queue)
+
+	if len(queue.PullRequests) != 1 ||
+		queue.PullRequests[0].PullRequestID != createPRDTO.ID ||
+		queue.PullRequests[0].Verdict == nil {
+		t.Fatalf("Reviewer with a verdict was not kept on the PR: %s", string(body))
+	}
+}
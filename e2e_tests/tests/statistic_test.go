@@ -0,0 +1,536 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
+func TestTeamStatistic(t *testing.T) {
+	author := dto.TeamMemberDTO{
+		ID:       "stat1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer1 := dto.TeamMemberDTO{
+		ID:       "stat1u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer2 := dto.TeamMemberDTO{
+		ID:       "stat1u3",
+		Username: "Carol",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamStat1",
+		Members: []dto.TeamMemberDTO{author, reviewer1, reviewer2},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	for _, prId := range []string{"statPR1", "statPR2"} {
+		url = os.Getenv("API_URL") + "/pullRequest/create"
+		resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestCreateDTO{
+			ID:       prId,
+			Name:     "pull req",
+			AuthorID: author.ID,
+		})
+		AssertStatusCode(t, resp, 201)
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/merge"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestMergeDTO{ID: "statPR1"})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/statistic/teams"
+	resp, body := MakeQueryRequest(t, "GET", url, nil)
+	AssertStatusCode(t, resp, 200)
+
+	var teamsStatistic dto.AllTeamsStatisticDTO
+	ParseJSONResponse(t, body, &teamsStatistic)
+
+	var found *dto.TeamStatisticDTO
+	for i := range teamsStatistic.Statistics {
+		if teamsStatistic.Statistics[i].TeamName == team.Name {
+			found = &teamsStatistic.Statistics[i]
+		}
+	}
+
+	if found == nil ||
+		found.PRsCreated != 2 ||
+		found.PRsMerged != 1 ||
+		found.PRsOpen != 1 ||
+		found.AvgReviewersPerPR != 2 ||
+		found.ActiveMembers != 3 {
+		t.Fatalf("Team statistic does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/statistic/team"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"name": team.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var teamStatistic dto.TeamDetailedStatisticDTO
+	ParseJSONResponse(t, body, &teamStatistic)
+
+	if teamStatistic.TeamName != team.Name ||
+		teamStatistic.PRsCreated != 2 ||
+		len(teamStatistic.Members) != 3 ||
+		teamStatistic.Members[0].UserID != author.ID ||
+		teamStatistic.Members[0].PRsCreated != 2 ||
+		teamStatistic.Members[0].ReviewsAssigned != 0 ||
+		teamStatistic.Members[1].ReviewsAssigned != 2 ||
+		teamStatistic.Members[1].OpenReviews != 1 {
+		t.Fatalf("Team member statistic does not match expected values: %s", string(body))
+	}
+
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"name": "NoSuchTeamStat"})
+	AssertStatusCode(t, resp, 404)
+}
+
+func TestStatistic_ActivityByPeriod(t *testing.T) {
+	author := dto.TeamMemberDTO{
+		ID:       "stat2u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer1 := dto.TeamMemberDTO{
+		ID:       "stat2u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer2 := dto.TeamMemberDTO{
+		ID:       "stat2u3",
+		Username: "Carol",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamStat2",
+		Members: []dto.TeamMemberDTO{author, reviewer1, reviewer2},
+	}
+
+	from := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestCreateDTO{
+		ID:       "stat2PR1",
+		Name:     "pull req",
+		AuthorID: author.ID,
+	})
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/merge"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestMergeDTO{ID: "stat2PR1"})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/statistic/users"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"from": from, "group_by": "day"})
+	AssertStatusCode(t, resp, 200)
+
+	var usersStatistic dto.AllUsersStatisticDTO
+	ParseJSONResponse(t, body, &usersStatistic)
+
+	var reviewerActivity, authorActivity *dto.ActivityDTO
+	for _, statistic := range usersStatistic.Statistics {
+		switch statistic.UserID {
+		case reviewer1.ID:
+			reviewerActivity = statistic.Activity
+		case author.ID:
+			authorActivity = statistic.Activity
+		}
+	}
+
+	if reviewerActivity == nil ||
+		reviewerActivity.Assigned != 1 ||
+		reviewerActivity.Merged != 1 ||
+		len(reviewerActivity.Series) != 1 ||
+		authorActivity == nil ||
+		authorActivity.Assigned != 0 {
+		t.Fatalf("User activity does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/statistic/team"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"name": team.Name, "from": from})
+	AssertStatusCode(t, resp, 200)
+
+	var teamStatistic dto.TeamDetailedStatisticDTO
+	ParseJSONResponse(t, body, &teamStatistic)
+
+	if teamStatistic.Activity == nil ||
+		teamStatistic.Activity.Assigned != 2 ||
+		teamStatistic.Activity.Merged != 1 ||
+		teamStatistic.Activity.Series != nil ||
+		teamStatistic.Members[1].Activity == nil ||
+		teamStatistic.Members[1].Activity.Merged != 1 {
+		t.Fatalf("Team activity does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/statistic/teams"
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{
+		"from": from,
+		"to":   time.Now().UTC().Add(-time.Hour).Format(time.RFC3339),
+	})
+	AssertStatusCode(t, resp, 400)
+
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"group_by": "year"})
+	AssertStatusCode(t, resp, 400)
+}
+
+func TestPRLifecycleStatistic(t *testing.T) {
+	members := []dto.TeamMemberDTO{
+		{ID: "stat3u1", Username: "Bob", IsActive: GetBoolPtr(true)},
+		{ID: "stat3u2", Username: "Alice", IsActive: GetBoolPtr(true)},
+		{ID: "stat3u3", Username: "Carol", IsActive: GetBoolPtr(true)},
+		{ID: "stat3u4", Username: "Dave", IsActive: GetBoolPtr(true)},
+	}
+
+	smallTeamMembers := []dto.TeamMemberDTO{
+		{ID: "stat3u5", Username: "Eve", IsActive: GetBoolPtr(true)},
+		{ID: "stat3u6", Username: "Frank", IsActive: GetBoolPtr(true)},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, dto.TeamDTO{Name: "TeamStat3", Members: members})
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.TeamDTO{Name: "TeamStat3Small", Members: smallTeamMembers})
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	resp, body := MakeJSONRequest(t, "POST", url, dto.PullRequestCreateDTO{
+		ID:       "stat3PR1",
+		Name:     "pull req",
+		AuthorID: members[0].ID,
+	})
+	AssertStatusCode(t, resp, 201)
+
+	var createdPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &createdPR)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestCreateDTO{
+		ID:       "stat3PR2",
+		Name:     "pull req",
+		AuthorID: smallTeamMembers[0].ID,
+	})
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/reassign"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestReassignDTO{
+		PullRequestID: "stat3PR1",
+		OldReviewerID: createdPR.PullRequest.Reviewers[0],
+	})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/pullRequest/merge"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestMergeDTO{ID: "stat3PR1"})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/statistic/pullRequests"
+	resp, body = MakeQueryRequest(t, "GET", url, nil)
+	AssertStatusCode(t, resp, 200)
+
+	var statistic dto.AllPRLifecycleStatisticDTO
+	ParseJSONResponse(t, body, &statistic)
+
+	teams := make(map[string]dto.PRLifecycleStatisticDTO)
+	for _, team := range statistic.Teams {
+		teams[team.TeamName] = team.PRLifecycleStatisticDTO
+	}
+
+	team := teams["TeamStat3"]
+	smallTeam := teams["TeamStat3Small"]
+
+	if team.PRsCreated != 1 ||
+		team.PRsMerged != 1 ||
+		team.TimeToMerge == nil ||
+		team.Reassignments != 1 ||
+		team.MaxReassignmentsPerPR != 1 ||
+		team.UnderstaffedShare == nil ||
+		*team.UnderstaffedShare != 0 ||
+		smallTeam.PRsCreated != 1 ||
+		smallTeam.TimeToMerge != nil ||
+		smallTeam.UnderstaffedPRs != 1 ||
+		smallTeam.UnderstaffedShare == nil ||
+		*smallTeam.UnderstaffedShare != 1 ||
+		statistic.Overall.PRsCreated < 2 {
+		t.Fatalf("PR lifecycle statistic does not match expected values: %s", string(body))
+	}
+
+	var authorFound bool
+	for _, author := range statistic.Authors {
+		if author.AuthorID == members[0].ID && author.PRsMerged == 1 && author.TimeToMerge != nil {
+			authorFound = true
+		}
+	}
+
+	if !authorFound {
+		t.Fatalf("Author statistic does not match expected values: %s", string(body))
+	}
+}
+
+func TestFairnessStatistic(t *testing.T) {
+	members := []dto.TeamMemberDTO{
+		{ID: "stat4u1", Username: "Bob", IsActive: GetBoolPtr(true)},
+		{ID: "stat4u2", Username: "Alice", IsActive: GetBoolPtr(true)},
+		{ID: "stat4u3", Username: "Carol", IsActive: GetBoolPtr(true)},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, dto.TeamDTO{Name: "TeamStat4", Members: members})
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestCreateDTO{
+		ID:       "stat4PR1",
+		Name:     "pull req",
+		AuthorID: members[0].ID,
+	})
+	AssertStatusCode(t, resp, 201)
+
+	findTeam := func(statistic dto.AllTeamsFairnessDTO) *dto.TeamFairnessDTO {
+		for i := range statistic.Teams {
+			if statistic.Teams[i].TeamName == "TeamStat4" {
+				return &statistic.Teams[i]
+			}
+		}
+
+		return nil
+	}
+
+	url = os.Getenv("API_URL") + "/statistic/fairness"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"threshold": "0.3"})
+	AssertStatusCode(t, resp, 200)
+
+	var statistic dto.AllTeamsFairnessDTO
+	ParseJSONResponse(t, body, &statistic)
+
+	team := findTeam(statistic)
+	if team == nil ||
+		team.Members != 3 ||
+		team.TotalReviews != 2 ||
+		team.MinLoad != 0 ||
+		team.MaxLoad != 1 ||
+		team.Gini != 0.33 ||
+		team.MaxMinRatio != nil ||
+		!team.Unfair {
+		t.Fatalf("Fairness statistic does not match expected values: %s", string(body))
+	}
+
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"threshold": "0.5"})
+	AssertStatusCode(t, resp, 200)
+
+	statistic = dto.AllTeamsFairnessDTO{}
+	ParseJSONResponse(t, body, &statistic)
+
+	team = findTeam(statistic)
+	if statistic.Threshold != 0.5 || team == nil || team.Unfair {
+		t.Fatalf("Fairness threshold was not applied: %s", string(body))
+	}
+
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"threshold": "2"})
+	AssertStatusCode(t, resp, 400)
+}
+
+func TestStatistic_Export(t *testing.T) {
+	team := dto.TeamDTO{
+		Name: "TeamExport",
+		Members: []dto.TeamMemberDTO{
+			{ID: "export_u1", Username: "Bob", IsActive: GetBoolPtr(true)},
+			{ID: "export_u2", Username: "Alice", IsActive: GetBoolPtr(true)},
+		},
+	}
+
+	resp, _ := MakeJSONRequest(t, "POST", os.Getenv("API_URL")+"/team/add", team)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", os.Getenv("API_URL")+"/pullRequest/create", dto.PullRequestCreateDTO{
+		ID:       "export_pr1",
+		Name:     "export",
+		AuthorID: "export_u1",
+	})
+	AssertStatusCode(t, resp, 201)
+
+	url := os.Getenv("API_URL") + "/statistic/users"
+	resp, body := MakeExportRequest(t, url, map[string]string{"from": "2000-01-01T00:00:00Z"}, "text/csv")
+	AssertStatusCode(t, resp, 200)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected CSV, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+
+	expectedHeader := []string{
+		"user_id", "assign_rate",
+		"activity.assigned", "activity.unassigned", "activity.merged", "activity.series",
+	}
+	if len(records) < 2 || !slices.Equal(records[0], expectedHeader) {
+		t.Fatalf("unexpected CSV header: %v", records)
+	}
+
+	found := false
+
+	for _, record := range records[1:] {
+		if record[0] == "export_u2" {
+			found = record[2] == "1"
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected one assignment of export_u2 in the CSV export")
+	}
+
+	url = os.Getenv("API_URL") + "/statistic/team"
+	resp, body = MakeExportRequest(t, url, map[string]string{"name": team.Name}, "application/x-ndjson")
+	AssertStatusCode(t, resp, 200)
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != len(team.Members) {
+		t.Fatalf("expected %d members, got %d lines", len(team.Members), len(lines))
+	}
+
+	for _, line := range lines {
+		var member dto.TeamMemberStatisticDTO
+		ParseJSONResponse(t, []byte(line), &member)
+
+		if member.UserID != "export_u1" && member.UserID != "export_u2" {
+			t.Fatalf("unexpected member %s", member.UserID)
+		}
+	}
+
+	resp, _ = MakeExportRequest(t, url, map[string]string{"name": team.Name}, "text/csv;q=0, application/json")
+	AssertStatusCode(t, resp, 200)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		t.Fatalf("expected JSON for refused CSV, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	resp, _ = MakeExportRequest(t, url, map[string]string{"name": team.Name}, "text/csv;q=0.5, application/x-ndjson")
+	AssertStatusCode(t, resp, 200)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/x-ndjson") {
+		t.Fatalf("expected NDJSON for the higher q-value, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	url = os.Getenv("API_URL") + "/statistic/team"
+	resp, _ = MakeExportRequest(t, url, map[string]string{"name": "TeamExportMissing"}, "text/csv")
+	AssertStatusCode(t, resp, 404)
+
+	url = os.Getenv("API_URL") + "/users/teams"
+	resp, body = MakeExportRequest(t, url, map[string]string{"user_id": "export_u1"}, "text/csv")
+	AssertStatusCode(t, resp, 200)
+
+	records, err = csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+
+	expectedRecords := [][]string{
+		{"user_id", "team_name", "primary"},
+		{"export_u1", team.Name, "true"},
+	}
+	if !slices.EqualFunc(records, expectedRecords, slices.Equal) {
+		t.Fatalf("unexpected user teams CSV: %v", records)
+	}
+}
+
+func TestReviewGraph(t *testing.T) {
+	team := dto.TeamDTO{
+		Name: "TeamGraph",
+		Members: []dto.TeamMemberDTO{
+			{ID: "graph_u1", Username: "Bob", IsActive: GetBoolPtr(true)},
+			{ID: "graph_u2", Username: "Alice", IsActive: GetBoolPtr(true)},
+		},
+	}
+
+	resp, _ := MakeJSONRequest(t, "POST", os.Getenv("API_URL")+"/team/add", team)
+	AssertStatusCode(t, resp, 201)
+
+	for _, prId := range []string{"graph_pr1", "graph_pr2"} {
+		resp, _ = MakeJSONRequest(t, "POST", os.Getenv("API_URL")+"/pullRequest/create", dto.PullRequestCreateDTO{
+			ID:       prId,
+			Name:     "graph",
+			AuthorID: "graph_u1",
+		})
+		AssertStatusCode(t, resp, 201)
+	}
+
+	url := os.Getenv("API_URL") + "/statistic/reviewGraph"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"team_name": team.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var graph dto.ReviewGraphDTO
+	ParseJSONResponse(t, body, &graph)
+
+	if len(graph.Nodes) != 2 || len(graph.Edges) != 1 {
+		t.Fatalf("expected 2 nodes and 1 edge, got %+v", graph)
+	}
+
+	expectedEdge := dto.ReviewGraphEdgeDTO{ReviewerID: "graph_u2", AuthorID: "graph_u1", Reviews: 2}
+	if graph.Edges[0] != expectedEdge {
+		t.Fatalf("unexpected edge %+v", graph.Edges[0])
+	}
+
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"team_name": team.Name, "format": "dot"})
+	AssertStatusCode(t, resp, 200)
+
+	if !strings.Contains(string(body), `"graph_u2" -> "graph_u1" [weight=2, label="2"];`) {
+		t.Fatalf("unexpected DOT output: %s", body)
+	}
+
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"team_name": team.Name, "format": "graphml"})
+	AssertStatusCode(t, resp, 200)
+
+	if !strings.Contains(string(body), `<edge source="graph_u2" target="graph_u1">`) {
+		t.Fatalf("unexpected GraphML output: %s", body)
+	}
+
+	resp, body = MakeExportRequest(t, url, map[string]string{"team_name": team.Name}, "text/vnd.graphviz")
+	AssertStatusCode(t, resp, 200)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/vnd.graphviz") ||
+		!strings.Contains(string(body), `"graph_u2" -> "graph_u1"`) {
+		t.Fatalf("expected DOT for the Accept header, got %s: %s", resp.Header.Get("Content-Type"), body)
+	}
+
+	resp, _ = MakeExportRequest(t, url, map[string]string{"team_name": team.Name}, "application/graphml+xml")
+	AssertStatusCode(t, resp, 200)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/graphml+xml") {
+		t.Fatalf("expected GraphML for the Accept header, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	resp, _ = MakeExportRequest(t, url, map[string]string{"team_name": team.Name, "format": "json"}, "text/vnd.graphviz")
+	AssertStatusCode(t, resp, 200)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		t.Fatalf("expected the format parameter to override Accept, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"format": "svg"})
+	AssertStatusCode(t, resp, 400)
+
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"team_name": "TeamGraphMissing"})
+	AssertStatusCode(t, resp, 404)
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
@@ -13,12 +14,14 @@ func TestCreateTeam(t *testing.T) {
 		ID:       "u1",
 		Username: "Bob",
 		IsActive: GetBoolPtr(true),
+		Role:     dto.TeamRoleMember,
 	}
 
 	teamMember2 := dto.TeamMemberDTO{
 		ID:       "u2",
 		Username: "Alice",
 		IsActive: GetBoolPtr(true),
+		Role:     dto.TeamRoleMember,
 	}
 
 	team := dto.TeamDTO{
@@ -103,12 +106,14 @@ func TestGetTeam(t *testing.T) {
 		ID:       "d1",
 		Username: "Bob",
 		IsActive: GetBoolPtr(true),
+		Role:     dto.TeamRoleMember,
 	}
 
 	teamMember2 := dto.TeamMemberDTO{
 		ID:       "d2",
 		Username: "Alice",
 		IsActive: GetBoolPtr(true),
+		Role:     dto.TeamRoleMember,
 	}
 
 	team := dto.TeamDTO{
@@ -145,3 +150,873 @@ func TestGetTeam_NotFound(t *testing.T) {
 	)
 	AssertStatusCode(t, resp, 404)
 }
+
+func TestDeactivateTeamUsers_NoCandidateLeftPending(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "bulkd1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember2 := dto.TeamMemberDTO{
+		ID:       "bulkd2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember3 := dto.TeamMemberDTO{
+		ID:       "bulkd3",
+		Username: "Eve",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamBulkDeact",
+		Members: []dto.TeamMemberDTO{teamMember1, teamMember2, teamMember3},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "bulkdPR1",
+		Name:     "pull req",
+		AuthorID: teamMember1.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/deactivateUsers"
+	deactivateDTO := dto.TeamDeactivateUsersDTO{
+		TeamName: team.Name,
+		UserIDs:  []string{teamMember2.ID, teamMember3.ID},
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, deactivateDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var result dto.TeamDeactivateUsersResultDTO
+	ParseJSONResponse(t, body, &result)
+
+	if len(result.DeactivatedIDs) != 2 ||
+		len(result.Reassignment.Reassigned) != 0 ||
+		len(result.Reassignment.NoCandidate) != 2 {
+		t.Fatalf("Deactivation result does not match expected values: %s", string(body))
+	}
+}
+
+func TestDeactivateTeamUsers_NotMember(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "bulkd11",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamBulkDeact2",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/deactivateUsers"
+	deactivateDTO := dto.TeamDeactivateUsersDTO{
+		TeamName: team.Name,
+		UserIDs:  []string{"u1"},
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, deactivateDTO)
+	AssertStatusCode(t, resp, 404)
+}
+
+func TestAddAndRemoveTeamMembers(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "roster1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember2 := dto.TeamMemberDTO{
+		ID:       "roster2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamRoster1",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/addMembers"
+	addDTO := dto.TeamMembersAddDTO{
+		TeamName: team.Name,
+		Members:  []dto.TeamMemberDTO{teamMember2},
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, addDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var updatedTeam dto.TeamDTO
+	ParseJSONResponse(t, body, &updatedTeam)
+
+	if len(updatedTeam.Members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(updatedTeam.Members))
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "rosterPR1",
+		Name:     "pull req",
+		AuthorID: teamMember1.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/removeMembers"
+	removeDTO := dto.TeamMembersRemoveDTO{
+		TeamName: team.Name,
+		UserIDs:  []string{teamMember2.ID},
+	}
+
+	resp, body = MakeJSONRequest(t, "POST", url, removeDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var removeResult dto.TeamMembersRemoveResultDTO
+	ParseJSONResponse(t, body, &removeResult)
+
+	if len(removeResult.Team.Members) != 1 ||
+		removeResult.Team.Members[0].ID != teamMember1.ID ||
+		len(removeResult.Reassignment.NoCandidate) != 1 {
+		t.Fatalf("Member removal result does not match expected values: %s", string(body))
+	}
+}
+
+func TestRenameTeam(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "rename1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamRenameOld",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/rename"
+	renameDTO := dto.TeamRenameDTO{
+		TeamName:    team.Name,
+		NewTeamName: "TeamRenameNew",
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, renameDTO)
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/team/get"
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"name": renameDTO.NewTeamName})
+	AssertStatusCode(t, resp, 200)
+
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"name": team.Name})
+	AssertStatusCode(t, resp, 404)
+}
+
+func TestArchiveTeam_BlocksPullRequests(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "archive1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamArchive1",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/archive"
+	resp, body := MakeJSONRequest(t, "POST", url, dto.TeamNameDTO{TeamName: team.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var status dto.TeamArchiveStatusDTO
+	ParseJSONResponse(t, body, &status)
+
+	if !status.Archived || status.ArchivedAt == nil {
+		t.Fatalf("expected team to be archived")
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "archivePR1",
+		Name:     "pull req",
+		AuthorID: teamMember1.ID,
+	}
+
+	resp, body = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 409)
+
+	var errorMessage dto.FullErrorDTO
+	ParseJSONResponse(t, body, &errorMessage)
+
+	if errorMessage.Error.Code != "TEAM_ARCHIVED" {
+		t.Fatalf("expected error code TEAM_ARCHIVED, got %s", errorMessage.Error.Code)
+	}
+}
+
+func TestDeleteTeam(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "delteam1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamDelete1",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/delete"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.TeamNameDTO{TeamName: team.Name})
+	AssertStatusCode(t, resp, 204)
+
+	url = os.Getenv("API_URL") + "/team/get"
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"name": team.Name})
+	AssertStatusCode(t, resp, 404)
+}
+
+func TestDeleteTeam_InUse(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "delteam11",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamDelete2",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "delteamPR1",
+		Name:     "pull req",
+		AuthorID: teamMember1.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/delete"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.TeamNameDTO{TeamName: team.Name})
+	AssertStatusCode(t, resp, 409)
+}
+
+func TestDeleteTeam_ResetsEscalationToDeletedBackup(t *testing.T) {
+	backup := dto.TeamMemberDTO{
+		ID:       "delteam21",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	member := dto.TeamMemberDTO{
+		ID:       "delteam22",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	deletedTeam := dto.TeamDTO{
+		Name:    "TeamDelete3",
+		Members: []dto.TeamMemberDTO{backup},
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamDelete4",
+		Members: []dto.TeamMemberDTO{member},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, deletedTeam)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/setSla"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.TeamSLAPolicyDTO{
+		TeamName:         team.Name,
+		ReviewSLA:        "48h",
+		Action:           dto.SLAActionEscalate,
+		BackupReviewerID: &backup.ID,
+	})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/team/delete"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.TeamNameDTO{TeamName: deletedTeam.Name})
+	AssertStatusCode(t, resp, 204)
+
+	url = os.Getenv("API_URL") + "/team/getSla"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"name": team.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var policy dto.TeamSLAPolicyDTO
+	ParseJSONResponse(t, body, &policy)
+
+	if policy.Action != dto.SLAActionReassign || policy.BackupReviewerID != nil {
+		t.Fatalf("SLA policy should fall back to reassignment: %s", string(body))
+	}
+}
+
+func TestParentTeam_ReviewerFallback(t *testing.T) {
+	parentMember := dto.TeamMemberDTO{
+		ID:       "parent1u1",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	squadMember1 := dto.TeamMemberDTO{
+		ID:       "squad1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	squadMember2 := dto.TeamMemberDTO{
+		ID:       "squad1u2",
+		Username: "Carol",
+		IsActive: GetBoolPtr(true),
+	}
+
+	parentTeam := dto.TeamDTO{
+		Name:    "TeamParent1",
+		Members: []dto.TeamMemberDTO{parentMember},
+	}
+
+	squad := dto.TeamDTO{
+		Name:           "TeamSquad1",
+		ParentTeamName: &parentTeam.Name,
+		Members:        []dto.TeamMemberDTO{squadMember1, squadMember2},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, parentTeam)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, squad)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "squadPR1",
+		Name:     "pull req",
+		AuthorID: squadMember1.ID,
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	var createdPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &createdPR)
+
+	reviewers := createdPR.PullRequest.Reviewers
+	if len(reviewers) != 2 ||
+		!slices.Contains(reviewers, squadMember2.ID) ||
+		!slices.Contains(reviewers, parentMember.ID) {
+		t.Fatalf("expected reviewers from squad and parent team, got %v", reviewers)
+	}
+}
+
+func TestParentTeam_ArchivedTeamSkipped(t *testing.T) {
+	parentMember := dto.TeamMemberDTO{
+		ID:       "parent2u1",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	author := dto.TeamMemberDTO{ID: "squad2u1", Username: "Bob", IsActive: GetBoolPtr(true)}
+	squadMembers := []dto.TeamMemberDTO{
+		{ID: "squad2u2", Username: "Carol", IsActive: GetBoolPtr(true)},
+		{ID: "squad2u3", Username: "Dave", IsActive: GetBoolPtr(true)},
+		{ID: "squad2u4", Username: "Erin", IsActive: GetBoolPtr(true)},
+	}
+
+	parentTeam := dto.TeamDTO{
+		Name:    "TeamParent2",
+		Members: []dto.TeamMemberDTO{parentMember},
+	}
+
+	squad := dto.TeamDTO{
+		Name:           "TeamSquad2",
+		ParentTeamName: &parentTeam.Name,
+		Members:        append([]dto.TeamMemberDTO{author}, squadMembers...),
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, parentTeam)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, squad)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "squadPR2",
+		Name:     "pull req",
+		AuthorID: author.ID,
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	var createdPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &createdPR)
+
+	url = os.Getenv("API_URL") + "/team/archive"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.TeamNameDTO{TeamName: squad.Name})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/pullRequest/reassign"
+	resp, body = MakeJSONRequest(t, "POST", url, dto.PullRequestReassignDTO{
+		PullRequestID: createPRDTO.ID,
+		OldReviewerID: createdPR.PullRequest.Reviewers[0],
+	})
+	AssertStatusCode(t, resp, 200)
+
+	var reassignedPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &reassignedPR)
+
+	if !slices.Contains(reassignedPR.PullRequest.Reviewers, parentMember.ID) {
+		t.Fatalf("expected a reviewer from the parent team, got %v", reassignedPR.PullRequest.Reviewers)
+	}
+}
+
+func TestSetParentTeam_Cycle(t *testing.T) {
+	teamA := dto.TeamDTO{
+		Name: "TeamCycleA",
+		Members: []dto.TeamMemberDTO{{
+			ID:       "cycleA1",
+			Username: "Bob",
+			IsActive: GetBoolPtr(true),
+		}},
+	}
+
+	teamB := dto.TeamDTO{
+		Name:           "TeamCycleB",
+		ParentTeamName: &teamA.Name,
+		Members: []dto.TeamMemberDTO{{
+			ID:       "cycleB1",
+			Username: "Bob",
+			IsActive: GetBoolPtr(true),
+		}},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, teamA)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, teamB)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/setParent"
+	setParentDTO := dto.TeamSetParentDTO{
+		TeamName:       teamA.Name,
+		ParentTeamName: &teamB.Name,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, setParentDTO)
+	AssertStatusCode(t, resp, 400)
+}
+
+func TestListTeams(t *testing.T) {
+	activeTeam := dto.TeamDTO{
+		Name: "ListPrefixActive",
+		Members: []dto.TeamMemberDTO{
+			{ID: "listu1", Username: "Bob", IsActive: GetBoolPtr(true)},
+			{ID: "listu2", Username: "Alice", IsActive: GetBoolPtr(false)},
+		},
+	}
+
+	archivedTeam := dto.TeamDTO{
+		Name: "ListPrefixArchived",
+		Members: []dto.TeamMemberDTO{
+			{ID: "listu3", Username: "Bob", IsActive: GetBoolPtr(true)},
+		},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, activeTeam)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, archivedTeam)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/archive"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.TeamNameDTO{TeamName: archivedTeam.Name})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/team/list"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"prefix": "ListPrefix"})
+	AssertStatusCode(t, resp, 200)
+
+	var teamList dto.TeamListDTO
+	ParseJSONResponse(t, body, &teamList)
+
+	if teamList.Total != 1 ||
+		len(teamList.Teams) != 1 ||
+		teamList.Teams[0].Name != activeTeam.Name ||
+		teamList.Teams[0].MembersCount != 2 ||
+		teamList.Teams[0].ActiveMembersCount != 1 ||
+		teamList.Teams[0].InactiveMembersCount != 1 {
+		t.Fatalf("Team list does not match expected values: %s", string(body))
+	}
+
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{
+		"prefix":           "ListPrefix",
+		"include_archived": "true",
+		"limit":            "1",
+		"offset":           "1",
+	})
+	AssertStatusCode(t, resp, 200)
+
+	ParseJSONResponse(t, body, &teamList)
+
+	if teamList.Total != 2 ||
+		len(teamList.Teams) != 1 ||
+		teamList.Teams[0].Name != archivedTeam.Name ||
+		!teamList.Teams[0].Archived {
+		t.Fatalf("Team list page does not match expected values: %s", string(body))
+	}
+}
+
+func TestSyncTeam(t *testing.T) {
+	syncDTO := dto.TeamSyncDTO{
+		Name: "TeamSync1",
+		Members: []dto.TeamMemberDTO{
+			{ID: "syncu1", Username: "Bob", IsActive: GetBoolPtr(true)},
+			{ID: "syncu2", Username: "Alice", IsActive: GetBoolPtr(true)},
+		},
+	}
+
+	url := os.Getenv("API_URL") + "/team/sync"
+	resp, body := MakeJSONRequest(t, "PUT", url, syncDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var result dto.TeamSyncResultDTO
+	ParseJSONResponse(t, body, &result)
+
+	if !result.TeamCreated || len(result.Changes.Added) != 2 || len(result.Team.Members) != 2 {
+		t.Fatalf("First sync result does not match expected values: %s", string(body))
+	}
+
+	resp, body = MakeJSONRequest(t, "PUT", url, syncDTO)
+	AssertStatusCode(t, resp, 200)
+
+	result = dto.TeamSyncResultDTO{}
+	ParseJSONResponse(t, body, &result)
+
+	if result.TeamCreated ||
+		len(result.Changes.Added) != 0 ||
+		len(result.Changes.Moved) != 0 ||
+		len(result.Changes.Updated) != 0 ||
+		len(result.Changes.Removed) != 0 {
+		t.Fatalf("Repeated sync should not change anything: %s", string(body))
+	}
+
+	syncDTO.Members = []dto.TeamMemberDTO{
+		{ID: "syncu1", Username: "Robert", IsActive: GetBoolPtr(true)},
+	}
+	syncDTO.RemoveAbsent = true
+
+	resp, body = MakeJSONRequest(t, "PUT", url, syncDTO)
+	AssertStatusCode(t, resp, 200)
+
+	result = dto.TeamSyncResultDTO{}
+	ParseJSONResponse(t, body, &result)
+
+	if len(result.Changes.Updated) != 1 ||
+		result.Changes.Updated[0] != "syncu1" ||
+		len(result.Changes.Removed) != 1 ||
+		result.Changes.Removed[0] != "syncu2" ||
+		len(result.Team.Members) != 1 ||
+		result.Team.Members[0].Username != "Robert" {
+		t.Fatalf("Sync with removal does not match expected values: %s", string(body))
+	}
+
+	syncDTO.Members = []dto.TeamMemberDTO{
+		{ID: "syncu1", Username: "Robert", IsActive: GetBoolPtr(true), Role: dto.TeamRoleLead},
+	}
+
+	resp, body = MakeJSONRequest(t, "PUT", url, syncDTO)
+	AssertStatusCode(t, resp, 200)
+
+	result = dto.TeamSyncResultDTO{}
+	ParseJSONResponse(t, body, &result)
+
+	if len(result.Changes.Updated) != 1 || result.Team.Members[0].Role != dto.TeamRoleLead {
+		t.Fatalf("Sync with a role should update it: %s", string(body))
+	}
+
+	syncDTO.Members[0].Role = ""
+
+	resp, body = MakeJSONRequest(t, "PUT", url, syncDTO)
+	AssertStatusCode(t, resp, 200)
+
+	result = dto.TeamSyncResultDTO{}
+	ParseJSONResponse(t, body, &result)
+
+	if len(result.Changes.Updated) != 0 || result.Team.Members[0].Role != dto.TeamRoleLead {
+		t.Fatalf("Sync without a role should keep the lead role: %s", string(body))
+	}
+}
+
+func TestTeamRoles_LeadOverridesAndObserverSkipped(t *testing.T) {
+	lead := dto.TeamMemberDTO{ID: "role1lead", Username: "Bob", IsActive: GetBoolPtr(true), Role: dto.TeamRoleLead}
+	author := dto.TeamMemberDTO{ID: "role1u1", Username: "Alice", IsActive: GetBoolPtr(true)}
+	member := dto.TeamMemberDTO{ID: "role1u2", Username: "Carol", IsActive: GetBoolPtr(true)}
+	spare := dto.TeamMemberDTO{ID: "role1u3", Username: "Erin", IsActive: GetBoolPtr(true)}
+	observer := dto.TeamMemberDTO{ID: "role1obs", Username: "Dave", IsActive: GetBoolPtr(true), Role: dto.TeamRoleObserver}
+	outsider := dto.TeamMemberDTO{ID: "role2u1", Username: "Frank", IsActive: GetBoolPtr(true)}
+
+	team := dto.TeamDTO{
+		Name:    "TeamRoles1",
+		Members: []dto.TeamMemberDTO{lead, author, member, spare, observer},
+	}
+
+	otherTeam := dto.TeamDTO{
+		Name:    "TeamRoles2",
+		Members: []dto.TeamMemberDTO{outsider},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, otherTeam)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "rolesPR1",
+		Name:     "pull req",
+		AuthorID: author.ID,
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	var createdPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &createdPR)
+
+	if len(createdPR.PullRequest.Reviewers) != 2 || slices.Contains(createdPR.PullRequest.Reviewers, observer.ID) {
+		t.Fatalf("expected two reviewers without the observer, got %v", createdPR.PullRequest.Reviewers)
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/overrideReviewer"
+	overrideDTO := dto.PullRequestOverrideReviewerDTO{
+		PullRequestID: createPRDTO.ID,
+		LeadID:        member.ID,
+		OldReviewerID: createdPR.PullRequest.Reviewers[0],
+		NewReviewerID: observer.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, overrideDTO)
+	AssertStatusCode(t, resp, 403)
+
+	overrideDTO.LeadID = lead.ID
+
+	resp, _ = MakeJSONRequest(t, "POST", url, overrideDTO)
+	AssertStatusCode(t, resp, 400)
+
+	overrideDTO.NewReviewerID = outsider.ID
+
+	resp, _ = MakeJSONRequest(t, "POST", url, overrideDTO)
+	AssertStatusCode(t, resp, 400)
+
+	for _, candidate := range []string{lead.ID, member.ID, spare.ID} {
+		if !slices.Contains(createdPR.PullRequest.Reviewers, candidate) {
+			overrideDTO.NewReviewerID = candidate
+		}
+	}
+
+	resp, body = MakeJSONRequest(t, "POST", url, overrideDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var overriddenPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &overriddenPR)
+
+	if !slices.Contains(overriddenPR.PullRequest.Reviewers, overrideDTO.NewReviewerID) ||
+		slices.Contains(overriddenPR.PullRequest.Reviewers, overrideDTO.OldReviewerID) {
+		t.Fatalf("expected the override to be applied, got %v", overriddenPR.PullRequest.Reviewers)
+	}
+
+	url = os.Getenv("API_URL") + "/team/setMemberRole"
+	roleDTO := dto.TeamSetMemberRoleDTO{
+		TeamName:     team.Name,
+		UserID:       member.ID,
+		Role:         dto.TeamRoleLead,
+		ActingUserID: author.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, roleDTO)
+	AssertStatusCode(t, resp, 403)
+
+	roleDTO.ActingUserID = ""
+
+	resp, _ = MakeJSONRequest(t, "POST", url, roleDTO)
+	AssertStatusCode(t, resp, 400)
+
+	roleDTO.ActingUserID = lead.ID
+
+	resp, body = MakeJSONRequest(t, "POST", url, roleDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var updatedMember dto.TeamMemberDTO
+	ParseJSONResponse(t, body, &updatedMember)
+
+	if updatedMember.ID != member.ID || updatedMember.Role != dto.TeamRoleLead {
+		t.Fatalf("Member role does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/team/removeMembers"
+	removeDTO := dto.TeamMembersRemoveDTO{
+		TeamName:     team.Name,
+		UserIDs:      []string{spare.ID},
+		ActingUserID: &author.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, removeDTO)
+	AssertStatusCode(t, resp, 403)
+
+	removeDTO.ActingUserID = &lead.ID
+
+	resp, _ = MakeJSONRequest(t, "POST", url, removeDTO)
+	AssertStatusCode(t, resp, 200)
+}
+
+func TestTeamSettings_AppliedOnAssignment(t *testing.T) {
+	parentTeam := dto.TeamDTO{
+		Name: "TeamSettingsParent",
+		Members: []dto.TeamMemberDTO{
+			{ID: "settp1u1", Username: "Alice", IsActive: GetBoolPtr(true)},
+			{ID: "settp1u2", Username: "Bob", IsActive: GetBoolPtr(true)},
+		},
+	}
+
+	squad := dto.TeamDTO{
+		Name:           "TeamSettingsSquad",
+		ParentTeamName: &parentTeam.Name,
+		Members: []dto.TeamMemberDTO{
+			{ID: "setts1u1", Username: "Carol", IsActive: GetBoolPtr(true)},
+			{ID: "setts1u2", Username: "Dave", IsActive: GetBoolPtr(true)},
+			{ID: "setts1u3", Username: "Eve", IsActive: GetBoolPtr(true)},
+			{ID: "setts1u4", Username: "Frank", IsActive: GetBoolPtr(true)},
+		},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, parentTeam)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, squad)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/team/getSettings"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"name": squad.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var settings dto.TeamSettingsDTO
+	ParseJSONResponse(t, body, &settings)
+
+	if settings.ReviewersCount != 2 ||
+		settings.Strategy != dto.ReviewerStrategyLeastLoaded ||
+		settings.OwnTeamRule != dto.OwnTeamPreferred {
+		t.Fatalf("Default settings do not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/team/setSettings"
+	reviewersCount := 6
+	updateDTO := dto.TeamSettingsUpdateDTO{
+		TeamName:       squad.Name,
+		ReviewersCount: &reviewersCount,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, updateDTO)
+	AssertStatusCode(t, resp, 400)
+
+	reviewersCount = 3
+
+	resp, body = MakeJSONRequest(t, "POST", url, updateDTO)
+	AssertStatusCode(t, resp, 200)
+
+	settings = dto.TeamSettingsDTO{}
+	ParseJSONResponse(t, body, &settings)
+
+	if settings.ReviewersCount != 3 || settings.Strategy != dto.ReviewerStrategyLeastLoaded {
+		t.Fatalf("Updated settings do not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "settingsPR1",
+		Name:     "pull req",
+		AuthorID: squad.Members[0].ID,
+	}
+
+	resp, body = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	var createdPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &createdPR)
+
+	if len(createdPR.PullRequest.Reviewers) != 3 {
+		t.Fatalf("expected three reviewers, got %v", createdPR.PullRequest.Reviewers)
+	}
+
+	url = os.Getenv("API_URL") + "/team/setSettings"
+	ownTeamRule := dto.OwnTeamExcluded
+	updateDTO = dto.TeamSettingsUpdateDTO{
+		TeamName:    squad.Name,
+		OwnTeamRule: &ownTeamRule,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, updateDTO)
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO.ID = "settingsPR2"
+
+	resp, body = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	createdPR = dto.FullPullRequestDTO{}
+	ParseJSONResponse(t, body, &createdPR)
+
+	reviewers := createdPR.PullRequest.Reviewers
+	if len(reviewers) != 2 ||
+		!slices.Contains(reviewers, parentTeam.Members[0].ID) ||
+		!slices.Contains(reviewers, parentTeam.Members[1].ID) {
+		t.Fatalf("expected reviewers from the parent team only, got %v", reviewers)
+	}
+}
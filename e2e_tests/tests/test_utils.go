@@ -105,6 +105,50 @@ func MakeQueryRequest(t *testing.T, method, baseURL string, queryParams map[stri
 	return resp, responseBody
 }
 
+// MakeExportRequest sends a GET request asking for the export format in the
+// Accept header.
+func MakeExportRequest(
+	t *testing.T,
+	baseURL string,
+	queryParams map[string]string,
+	accept string,
+) (*http.Response, []byte) {
+	t.Helper()
+
+	url, err := url.Parse(baseURL)
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	query := url.Query()
+	for key, value := range queryParams {
+		query.Add(key, value)
+	}
+
+	url.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Accept", accept)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response body: %v", err)
+	}
+
+	return resp, responseBody
+}
+
 func GetBoolPtr(b bool) *bool {
 	return &b
 }
@@ -106,3 +106,728 @@ func TestGetPr(t *testing.T) {
 		t.Fatalf("User PRs data does not match expected values")
 	}
 }
+
+func TestSetIsActive_ReassignsOpenReviews(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "dea11ct1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember2 := dto.TeamMemberDTO{
+		ID:       "dea11ct2",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember3 := dto.TeamMemberDTO{
+		ID:       "dea11ct3",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember4 := dto.TeamMemberDTO{
+		ID:       "dea11ct4",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamDeact1ve",
+		Members: []dto.TeamMemberDTO{teamMember1, teamMember2, teamMember3, teamMember4},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "deactPR1",
+		Name:     "pull req",
+		AuthorID: teamMember1.ID,
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	var fetchedFullPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &fetchedFullPR)
+	deactivatedId := fetchedFullPR.PullRequest.Reviewers[0]
+
+	url = os.Getenv("API_URL") + "/users/setIsActive"
+	setActiveDTO := dto.UserSetIsActiveDTO{
+		UserID:   deactivatedId,
+		IsActive: GetBoolPtr(false),
+	}
+
+	resp, body = MakeJSONRequest(t, "POST", url, setActiveDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var result dto.UserSetIsActiveResultDTO
+	ParseJSONResponse(t, body, &result)
+
+	if result.Reassignment == nil ||
+		len(result.Reassignment.Reassigned) != 1 ||
+		len(result.Reassignment.NoCandidate) != 0 ||
+		result.Reassignment.Reassigned[0].PullRequestID != createPRDTO.ID ||
+		result.Reassignment.Reassigned[0].NewReviewerID == deactivatedId {
+		t.Fatalf("Reassignment report does not match expected values: %s", string(body))
+	}
+}
+
+func TestMoveTeam(t *testing.T) {
+	teamMember1 := dto.TeamMemberDTO{
+		ID:       "move1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	teamMember2 := dto.TeamMemberDTO{
+		ID:       "move1u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	oldTeam := dto.TeamDTO{
+		Name:    "TeamMoveOld",
+		Members: []dto.TeamMemberDTO{teamMember1},
+	}
+
+	newTeam := dto.TeamDTO{
+		Name:    "TeamMoveNew",
+		Members: []dto.TeamMemberDTO{teamMember2},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, oldTeam)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, newTeam)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/users/moveTeam"
+	moveDTO := dto.UserMoveTeamDTO{
+		UserID:          teamMember1.ID,
+		TeamName:        newTeam.Name,
+		ReassignReviews: true,
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, moveDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var movedUser dto.UserMoveTeamResultDTO
+	ParseJSONResponse(t, body, &movedUser)
+
+	if movedUser.TeamName != newTeam.Name ||
+		movedUser.PreviousTeamName != oldTeam.Name ||
+		movedUser.Reassignment == nil {
+		t.Fatalf("Moved user data does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/users/teamHistory"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": teamMember1.ID})
+	AssertStatusCode(t, resp, 200)
+
+	var history dto.UserTeamHistoryDTO
+	ParseJSONResponse(t, body, &history)
+
+	if len(history.Changes) != 1 ||
+		history.Changes[0].FromTeamName == nil ||
+		*history.Changes[0].FromTeamName != oldTeam.Name ||
+		history.Changes[0].ToTeamName == nil ||
+		*history.Changes[0].ToTeamName != newTeam.Name {
+		t.Fatalf("Team history does not match expected values: %s", string(body))
+	}
+}
+
+func TestJoinTeam_PullRequestTeamContext(t *testing.T) {
+	platformEngineer := dto.TeamMemberDTO{
+		ID:       "multi1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	productMember := dto.TeamMemberDTO{
+		ID:       "multi1u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	platformTeam := dto.TeamDTO{
+		Name:    "TeamMultiPlatform",
+		Members: []dto.TeamMemberDTO{platformEngineer},
+	}
+
+	productTeam := dto.TeamDTO{
+		Name:    "TeamMultiProduct",
+		Members: []dto.TeamMemberDTO{productMember},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, platformTeam)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, productTeam)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "multiPR1",
+		Name:     "pull req",
+		AuthorID: platformEngineer.ID,
+		TeamName: &productTeam.Name,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 400)
+
+	url = os.Getenv("API_URL") + "/users/joinTeam"
+	membershipDTO := dto.UserTeamMembershipDTO{
+		UserID:   platformEngineer.ID,
+		TeamName: productTeam.Name,
+	}
+
+	resp, body := MakeJSONRequest(t, "POST", url, membershipDTO)
+	AssertStatusCode(t, resp, 200)
+
+	var userTeams dto.UserTeamsDTO
+	ParseJSONResponse(t, body, &userTeams)
+
+	if userTeams.PrimaryTeamName != platformTeam.Name ||
+		len(userTeams.AdditionalTeamNames) != 1 ||
+		userTeams.AdditionalTeamNames[0] != productTeam.Name {
+		t.Fatalf("User teams do not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	resp, body = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	var createdPR dto.FullPullRequestDTO
+	ParseJSONResponse(t, body, &createdPR)
+
+	if len(createdPR.PullRequest.Reviewers) != 1 ||
+		createdPR.PullRequest.Reviewers[0] != productMember.ID {
+		t.Fatalf("expected reviewer from the product team, got %v", createdPR.PullRequest.Reviewers)
+	}
+}
+
+func TestMoveTeam_IntoAdditionalTeam(t *testing.T) {
+	engineer := dto.TeamMemberDTO{
+		ID:       "multi2u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	productMember := dto.TeamMemberDTO{
+		ID:       "multi2u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	platformTeam := dto.TeamDTO{
+		Name:    "TeamMulti2Platform",
+		Members: []dto.TeamMemberDTO{engineer},
+	}
+
+	productTeam := dto.TeamDTO{
+		Name:    "TeamMulti2Product",
+		Members: []dto.TeamMemberDTO{productMember},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, platformTeam)
+	AssertStatusCode(t, resp, 201)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, productTeam)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/users/joinTeam"
+	membershipDTO := dto.UserTeamMembershipDTO{
+		UserID:   engineer.ID,
+		TeamName: productTeam.Name,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, membershipDTO)
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/users/moveTeam"
+	moveDTO := dto.UserMoveTeamDTO{
+		UserID:   engineer.ID,
+		TeamName: productTeam.Name,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, moveDTO)
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/users/teams"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"user_id": engineer.ID})
+	AssertStatusCode(t, resp, 200)
+
+	var userTeams dto.UserTeamsDTO
+	ParseJSONResponse(t, body, &userTeams)
+
+	if userTeams.PrimaryTeamName != productTeam.Name || len(userTeams.AdditionalTeamNames) != 0 {
+		t.Fatalf("Additional membership should be replaced by the primary team: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/team/get"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"team_name": productTeam.Name})
+	AssertStatusCode(t, resp, 200)
+
+	var team dto.TeamDTO
+	ParseJSONResponse(t, body, &team)
+
+	if len(team.Members) != 2 {
+		t.Fatalf("expected each member to be listed once, got %s", string(body))
+	}
+}
+
+func TestUserProfile_UpdateAndAnonymize(t *testing.T) {
+	author := dto.TeamMemberDTO{
+		ID:       "prof1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer := dto.TeamMemberDTO{
+		ID:       "prof1u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamProfile1",
+		Members: []dto.TeamMemberDTO{author, reviewer},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/users/update"
+	email := "alice@example.com"
+	fullName := "Alice Smith"
+	updateDTO := dto.UserUpdateDTO{
+		UserID:   reviewer.ID,
+		Email:    &email,
+		FullName: &fullName,
+	}
+
+	resp, body := MakeJSONRequest(t, "PATCH", url, updateDTO)
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/users/get"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer.ID})
+	AssertStatusCode(t, resp, 200)
+
+	var user dto.UserDTO
+	ParseJSONResponse(t, body, &user)
+
+	if user.Username != reviewer.Username ||
+		user.TeamName != team.Name ||
+		user.Email == nil || *user.Email != email ||
+		user.FullName == nil || *user.FullName != fullName {
+		t.Fatalf("User profile does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/create"
+	createPRDTO := dto.PullRequestCreateDTO{
+		ID:       "profPR1",
+		Name:     "pull req",
+		AuthorID: author.ID,
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, createPRDTO)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/users/anonymize"
+	resp, body = MakeJSONRequest(t, "POST", url, dto.UserIDDTO{UserID: reviewer.ID})
+	AssertStatusCode(t, resp, 200)
+
+	var anonymized dto.UserAnonymizeResultDTO
+	ParseJSONResponse(t, body, &anonymized)
+
+	if anonymized.Username == reviewer.Username ||
+		anonymized.Email != nil ||
+		anonymized.FullName != nil ||
+		anonymized.IsActive ||
+		anonymized.TeamName != "" ||
+		anonymized.AnonymizedAt == nil ||
+		anonymized.Reassignment == nil ||
+		len(anonymized.Reassignment.NoCandidate) != 1 {
+		t.Fatalf("Anonymized user does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/users/getReview"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer.ID})
+	AssertStatusCode(t, resp, 200)
+
+	var userPRs dto.UserPRsDTO
+	ParseJSONResponse(t, body, &userPRs)
+
+	if len(userPRs.PullRequests) != 1 || userPRs.PullRequests[0].ID != createPRDTO.ID {
+		t.Fatalf("Review history should be kept: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/users/update"
+	resp, _ = MakeJSONRequest(t, "PATCH", url, updateDTO)
+	AssertStatusCode(t, resp, 409)
+}
+
+func TestUserProfile_ClearEmail(t *testing.T) {
+	member := dto.TeamMemberDTO{
+		ID:       "prof2u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamProfile2",
+		Members: []dto.TeamMemberDTO{member},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/users/update"
+	email := "bob@example.com"
+	resp, _ = MakeJSONRequest(t, "PATCH", url, dto.UserUpdateDTO{UserID: member.ID, Email: &email})
+	AssertStatusCode(t, resp, 200)
+
+	invalidEmail := "not-an-email"
+	resp, _ = MakeJSONRequest(t, "PATCH", url, dto.UserUpdateDTO{UserID: member.ID, Email: &invalidEmail})
+	AssertStatusCode(t, resp, 400)
+
+	emptyEmail := ""
+	resp, body := MakeJSONRequest(t, "PATCH", url, dto.UserUpdateDTO{UserID: member.ID, Email: &emptyEmail})
+	AssertStatusCode(t, resp, 200)
+
+	var user dto.UserDTO
+	ParseJSONResponse(t, body, &user)
+
+	if user.Email != nil {
+		t.Fatalf("Email should be cleared: %s", string(body))
+	}
+}
+
+func TestGetReviews_FiltersAndPagination(t *testing.T) {
+	author := dto.TeamMemberDTO{
+		ID:       "page1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer := dto.TeamMemberDTO{
+		ID:       "page1u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamPaging1",
+		Members: []dto.TeamMemberDTO{author, reviewer},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	prIds := []string{"pagePR1", "pagePR2", "pagePR3"}
+	for _, prId := range prIds {
+		url = os.Getenv("API_URL") + "/pullRequest/create"
+		resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestCreateDTO{
+			ID:       prId,
+			Name:     "pull req",
+			AuthorID: author.ID,
+		})
+		AssertStatusCode(t, resp, 201)
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/merge"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestMergeDTO{ID: prIds[0]})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/users/getReview"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer.ID, "limit": "2"})
+	AssertStatusCode(t, resp, 200)
+
+	var firstPage dto.UserPRsDTO
+	ParseJSONResponse(t, body, &firstPage)
+
+	if len(firstPage.PullRequests) != 2 ||
+		firstPage.PullRequests[0].ID != prIds[2] ||
+		firstPage.PullRequests[1].ID != prIds[1] ||
+		firstPage.PullRequests[0].CreatedAt.IsZero() ||
+		firstPage.NextCursor == "" {
+		t.Fatalf("First page does not match expected values: %s", string(body))
+	}
+
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{
+		"user_id": reviewer.ID,
+		"limit":   "2",
+		"cursor":  firstPage.NextCursor,
+	})
+	AssertStatusCode(t, resp, 200)
+
+	var secondPage dto.UserPRsDTO
+	ParseJSONResponse(t, body, &secondPage)
+
+	if len(secondPage.PullRequests) != 1 ||
+		secondPage.PullRequests[0].ID != prIds[0] ||
+		secondPage.PullRequests[0].MergedAt == nil ||
+		secondPage.NextCursor != "" {
+		t.Fatalf("Second page does not match expected values: %s", string(body))
+	}
+
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer.ID, "status": "MERGED"})
+	AssertStatusCode(t, resp, 200)
+
+	var mergedPRs dto.UserPRsDTO
+	ParseJSONResponse(t, body, &mergedPRs)
+
+	if len(mergedPRs.PullRequests) != 1 || mergedPRs.PullRequests[0].ID != prIds[0] {
+		t.Fatalf("Status filter does not match expected values: %s", string(body))
+	}
+
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer.ID, "cursor": "broken"})
+	AssertStatusCode(t, resp, 400)
+
+	url = os.Getenv("API_URL") + "/users/getAuthored"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": author.ID, "order": "asc"})
+	AssertStatusCode(t, resp, 200)
+
+	var authoredPRs dto.UserPRsDTO
+	ParseJSONResponse(t, body, &authoredPRs)
+
+	if len(authoredPRs.PullRequests) != 3 || authoredPRs.PullRequests[0].ID != prIds[0] {
+		t.Fatalf("Authored PRs do not match expected values: %s", string(body))
+	}
+}
+
+func TestGetReviewQueue_PriorityAndVerdicts(t *testing.T) {
+	author := dto.TeamMemberDTO{
+		ID:       "queue1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer1 := dto.TeamMemberDTO{
+		ID:       "queue1u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	reviewer2 := dto.TeamMemberDTO{
+		ID:       "queue1u3",
+		Username: "Carol",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamQueue1",
+		Members: []dto.TeamMemberDTO{author, reviewer1, reviewer2},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	prs := []dto.PullRequestCreateDTO{
+		{ID: "queuePR1", Name: "low", AuthorID: author.ID, Priority: dto.PriorityLow},
+		{ID: "queuePR2", Name: "urgent", AuthorID: author.ID, Priority: dto.PriorityUrgent},
+		{ID: "queuePR3", Name: "default", AuthorID: author.ID},
+	}
+	for _, pr := range prs {
+		url = os.Getenv("API_URL") + "/pullRequest/create"
+		resp, _ = MakeJSONRequest(t, "POST", url, pr)
+		AssertStatusCode(t, resp, 201)
+	}
+
+	url = os.Getenv("API_URL") + "/users/reviewQueue"
+	resp, body := MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer1.ID})
+	AssertStatusCode(t, resp, 200)
+
+	var queue dto.UserReviewQueueDTO
+	ParseJSONResponse(t, body, &queue)
+
+	if len(queue.PullRequests) != 3 ||
+		queue.PullRequests[0].PullRequestID != "queuePR2" ||
+		queue.PullRequests[1].PullRequestID != "queuePR3" ||
+		queue.PullRequests[1].Priority != dto.PriorityNormal ||
+		queue.PullRequests[2].PullRequestID != "queuePR1" ||
+		len(queue.PullRequests[0].OtherReviewers) != 1 ||
+		queue.PullRequests[0].OtherReviewers[0].UserID != reviewer2.ID {
+		t.Fatalf("Review queue does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/pullRequest/setPriority"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestSetPriorityDTO{
+		PullRequestID: "queuePR1",
+		Priority:      dto.PriorityHigh,
+	})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/pullRequest/submitVerdict"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestVerdictDTO{
+		PullRequestID: "queuePR2",
+		ReviewerID:    reviewer1.ID,
+		Verdict:       dto.VerdictApproved,
+	})
+	AssertStatusCode(t, resp, 200)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.PullRequestVerdictDTO{
+		PullRequestID: "queuePR2",
+		ReviewerID:    author.ID,
+		Verdict:       dto.VerdictApproved,
+	})
+	AssertStatusCode(t, resp, 409)
+
+	url = os.Getenv("API_URL") + "/users/reviewQueue"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer1.ID})
+	AssertStatusCode(t, resp, 200)
+
+	queue = dto.UserReviewQueueDTO{}
+	ParseJSONResponse(t, body, &queue)
+
+	if len(queue.PullRequests) != 2 ||
+		queue.PullRequests[0].PullRequestID != "queuePR1" ||
+		queue.PullRequests[1].PullRequestID != "queuePR3" {
+		t.Fatalf("Review queue does not match expected values after verdict: %s", string(body))
+	}
+
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer2.ID})
+	AssertStatusCode(t, resp, 200)
+
+	queue = dto.UserReviewQueueDTO{}
+	ParseJSONResponse(t, body, &queue)
+
+	if len(queue.PullRequests) != 3 ||
+		queue.PullRequests[0].PullRequestID != "queuePR2" ||
+		len(queue.PullRequests[0].OtherReviewers) != 1 ||
+		queue.PullRequests[0].OtherReviewers[0].Verdict == nil ||
+		*queue.PullRequests[0].OtherReviewers[0].Verdict != dto.VerdictApproved {
+		t.Fatalf("Other reviewers' verdicts do not match expected values: %s", string(body))
+	}
+
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": reviewer1.ID, "include_reviewed": "true"})
+	AssertStatusCode(t, resp, 200)
+
+	queue = dto.UserReviewQueueDTO{}
+	ParseJSONResponse(t, body, &queue)
+
+	if len(queue.PullRequests) != 3 ||
+		queue.PullRequests[0].PullRequestID != "queuePR2" ||
+		queue.PullRequests[0].Verdict == nil {
+		t.Fatalf("Review queue with reviewed PRs does not match expected values: %s", string(body))
+	}
+}
+
+func TestUserIdentities_LinkAndResolve(t *testing.T) {
+	user1 := dto.TeamMemberDTO{
+		ID:       "ident1u1",
+		Username: "Bob",
+		IsActive: GetBoolPtr(true),
+	}
+
+	user2 := dto.TeamMemberDTO{
+		ID:       "ident1u2",
+		Username: "Alice",
+		IsActive: GetBoolPtr(true),
+	}
+
+	team := dto.TeamDTO{
+		Name:    "TeamIdent1",
+		Members: []dto.TeamMemberDTO{user1, user2},
+	}
+
+	url := os.Getenv("API_URL") + "/team/add"
+	resp, _ := MakeJSONRequest(t, "POST", url, team)
+	AssertStatusCode(t, resp, 201)
+
+	url = os.Getenv("API_URL") + "/users/linkIdentity"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.UserIdentityLinkDTO{
+		UserID:     user1.ID,
+		Provider:   dto.IdentityGitHub,
+		ExternalID: "Ident1-Bob",
+	})
+	AssertStatusCode(t, resp, 200)
+
+	resp, body := MakeJSONRequest(t, "POST", url, dto.UserIdentityLinkDTO{
+		UserID:     user1.ID,
+		Provider:   dto.IdentityGitLab,
+		ExternalID: "4242001",
+	})
+	AssertStatusCode(t, resp, 200)
+
+	var identities dto.UserIdentitiesDTO
+	ParseJSONResponse(t, body, &identities)
+
+	if identities.UserID != user1.ID ||
+		len(identities.Identities) != 2 ||
+		identities.Identities[0].Provider != dto.IdentityGitHub ||
+		identities.Identities[0].ExternalID != "ident1-bob" {
+		t.Fatalf("Identities do not match expected values: %s", string(body))
+	}
+
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.UserIdentityLinkDTO{
+		UserID:     user2.ID,
+		Provider:   dto.IdentityGitHub,
+		ExternalID: "ident1-bob",
+	})
+	AssertStatusCode(t, resp, 409)
+
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.UserIdentityLinkDTO{
+		UserID:     user2.ID,
+		Provider:   dto.IdentityGitLab,
+		ExternalID: "alice",
+	})
+	AssertStatusCode(t, resp, 400)
+
+	url = os.Getenv("API_URL") + "/users/resolve"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{
+		"provider":    string(dto.IdentityGitHub),
+		"external_id": "IDENT1-BOB",
+	})
+	AssertStatusCode(t, resp, 200)
+
+	var resolvedUser dto.UserDTO
+	ParseJSONResponse(t, body, &resolvedUser)
+
+	if resolvedUser.ID != user1.ID || resolvedUser.TeamName != team.Name {
+		t.Fatalf("Resolved user does not match expected values: %s", string(body))
+	}
+
+	url = os.Getenv("API_URL") + "/users/unlinkIdentity"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.UserIdentityLinkDTO{
+		UserID:     user1.ID,
+		Provider:   dto.IdentityGitLab,
+		ExternalID: "4242001",
+	})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/users/resolve"
+	resp, _ = MakeQueryRequest(t, "GET", url, map[string]string{
+		"provider":    string(dto.IdentityGitLab),
+		"external_id": "4242001",
+	})
+	AssertStatusCode(t, resp, 404)
+
+	url = os.Getenv("API_URL") + "/users/anonymize"
+	resp, _ = MakeJSONRequest(t, "POST", url, dto.UserIDDTO{UserID: user1.ID})
+	AssertStatusCode(t, resp, 200)
+
+	url = os.Getenv("API_URL") + "/users/identities"
+	resp, body = MakeQueryRequest(t, "GET", url, map[string]string{"user_id": user1.ID})
+	AssertStatusCode(t, resp, 200)
+
+	identities = dto.UserIdentitiesDTO{}
+	ParseJSONResponse(t, body, &identities)
+
+	if len(identities.Identities) != 0 {
+		t.Fatalf("Anonymized user still has identities: %s", string(body))
+	}
+}
@@ -15,12 +15,15 @@ import (
 	"github.com/L11D/avito-review-assign-service/internal/config"
 	"github.com/L11D/avito-review-assign-service/internal/http/handlers"
 	"github.com/L11D/avito-review-assign-service/internal/http/middleware"
+	"github.com/L11D/avito-review-assign-service/internal/metrics"
 	"github.com/L11D/avito-review-assign-service/internal/migrations"
 	"github.com/L11D/avito-review-assign-service/internal/repo"
 	"github.com/L11D/avito-review-assign-service/internal/services"
+	"github.com/L11D/avito-review-assign-service/internal/workers"
 	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
 	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func Run() {
@@ -35,26 +38,18 @@ func Run() {
 		os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	defer stop()
 
-	db, err := sqlx.Connect("postgres", config.GetDBSource())
+	db, err := openDB(config)
 	if err != nil {
-		slog.Error("Failed to connect to the database", slog.String("error", err.Error()))
+		slog.Error("Failed to prepare the database", slog.String("error", err.Error()))
 
 		return
 	}
 
-	defer func() {
-		if err := db.Close(); err != nil {
-			slog.Error("Failed to close database connection", slog.String("error", err.Error()))
-		}
-	}()
+	defer closeDB(db)
 
-	if err := migrations.RunMigrations(db.DB); err != nil {
-		slog.Error("Failed to run migrations", slog.String("error", err.Error()))
+	r, slaWorker := initDependencies(db, config)
 
-		return
-	}
-
-	r := initDependencies(db)
+	go slaWorker.Run(ctx)
 
 	server := &http.Server{
 		Addr:              ":" + config.HTTPPort,
@@ -86,34 +81,138 @@ func Run() {
 	slog.Info("Application stopped")
 }
 
-func initDependencies(db *sqlx.DB) *gin.Engine {
-	trManager := manager.Must(trmsqlx.NewDefaultFactory(db))
+type slaService interface {
+	handlers.SLAService
+	workers.SLAService
+}
+
+type appServices struct {
+	user        handlers.UserService
+	team        handlers.TeamService
+	pullRequest handlers.PullRequestService
+	statistic   handlers.StatisticService
+	sla         slaService
+	trManager   *manager.Manager
+}
+
+// openDB connects to the database and brings its schema up to date.
+func openDB(config *config.Config) (*sqlx.DB, error) {
+	db, err := sqlx.Connect("postgres", config.GetDBSource())
+	if err != nil {
+		return nil, err
+	}
+
+	err = migrations.RunMigrations(db.DB)
+	if err != nil {
+		closeDB(db)
+
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func closeDB(db *sqlx.DB) {
+	if err := db.Close(); err != nil {
+		slog.Error("Failed to close database connection", slog.String("error", err.Error()))
+	}
+}
+
+func initDependencies(db *sqlx.DB, config *config.Config) (*gin.Engine, *workers.SLAWorker) {
+	deps := initServices(db, config)
+
+	r := gin.New()
+	r.Use(middleware.MetricsMiddleware())
+	r.Use(gin.Recovery())
+	r.Use(middleware.ErrorMiddleware())
+	r.GET("/health", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"status": "healthy"}) })
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	handlers.NewUserHandler(deps.user).RegisterRoutes(r)
+	handlers.NewTeamHandler(deps.team).RegisterRoutes(r)
+	handlers.NewPullRequestHandler(deps.pullRequest).RegisterRoutes(r)
+	handlers.NewStatisticHandler(deps.statistic).RegisterRoutes(r)
+	handlers.NewSLAHandler(deps.sla).RegisterRoutes(r)
+
+	return r, workers.NewSLAWorker(deps.sla, config.SLACheckInterval)
+}
+
+func initServices(db *sqlx.DB, config *config.Config) appServices {
+	trManager := manager.Must(metrics.TrFactory(trmsqlx.NewDefaultFactory(db)))
 
 	userRepo := repo.NewUserRepo(db, trmsqlx.DefaultCtxGetter)
 	teamRepo := repo.NewTeamRepo(db, trmsqlx.DefaultCtxGetter)
 	pullRequestRepo := repo.NewPullRequestRepo(db, trmsqlx.DefaultCtxGetter)
 	pullRequestReviewerRepo := repo.NewPullRequestReviewerRepo(db, trmsqlx.DefaultCtxGetter)
-
-	userService := services.NewUserService(userRepo, teamRepo, pullRequestRepo, trManager)
-	teamService := services.NewTeamService(teamRepo, userService, trManager)
+	pullRequestDeclineRepo := repo.NewPullRequestDeclineRepo(db, trmsqlx.DefaultCtxGetter)
+	teamSLAPolicyRepo := repo.NewTeamSLAPolicyRepo(db, trmsqlx.DefaultCtxGetter)
+	reviewSLAEventRepo := repo.NewReviewSLAEventRepo(db, trmsqlx.DefaultCtxGetter)
+	teamMembershipHistoryRepo := repo.NewTeamMembershipHistoryRepo(db, trmsqlx.DefaultCtxGetter)
+	teamMembershipRepo := repo.NewTeamMembershipRepo(db, trmsqlx.DefaultCtxGetter)
+	teamSettingsRepo := repo.NewTeamSettingsRepo(db, trmsqlx.DefaultCtxGetter)
+	userIdentityRepo := repo.NewUserIdentityRepo(db, trmsqlx.DefaultCtxGetter)
+	statisticRepo := repo.NewStatisticRepo(db, trmsqlx.DefaultCtxGetter)
+	reviewEventRepo := repo.NewReviewEventRepo(db, trmsqlx.DefaultCtxGetter)
+
+	reviewerService := services.NewReviewerService(
+		userRepo,
+		teamRepo,
+		teamSettingsRepo,
+		pullRequestRepo,
+		pullRequestReviewerRepo,
+		pullRequestDeclineRepo,
+		reviewEventRepo,
+		config.FallbackDepth,
+		trManager,
+	)
+	userService := services.NewUserService(
+		userRepo,
+		teamRepo,
+		pullRequestRepo,
+		pullRequestReviewerRepo,
+		teamMembershipHistoryRepo,
+		teamMembershipRepo,
+		userIdentityRepo,
+		reviewerService,
+		trManager,
+	)
+	teamService := services.NewTeamService(teamRepo, teamSettingsRepo, teamSLAPolicyRepo, userService, trManager)
 	pullService := services.NewPullRequestService(
 		pullRequestRepo,
 		pullRequestReviewerRepo,
+		pullRequestDeclineRepo,
+		reviewEventRepo,
 		userRepo,
+		teamRepo,
+		teamMembershipRepo,
 		userService,
+		reviewerService,
+		trManager,
+	)
+	slaService := services.NewSLAService(
+		teamRepo,
+		teamSLAPolicyRepo,
+		pullRequestReviewerRepo,
+		pullRequestDeclineRepo,
+		reviewSLAEventRepo,
+		userRepo,
+		reviewerService,
+		trManager,
+	)
+	statisticService := services.NewStatisticService(
+		pullRequestDeclineRepo,
+		statisticRepo,
+		teamRepo,
+		config.FairnessThreshold,
 		trManager,
 	)
-	statisticService := services.NewStatisticService(userRepo, trManager)
-
-	r := gin.New()
-	r.Use(gin.Recovery())
-	r.Use(middleware.ErrorMiddleware())
-	r.GET("/health", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"status": "healthy"}) })
-
-	handlers.NewUserHandler(userService).RegisterRoutes(r)
-	handlers.NewTeamHandler(teamService).RegisterRoutes(r)
-	handlers.NewPullRequestHandler(pullService).RegisterRoutes(r)
-	handlers.NewStatisticHandler(statisticService).RegisterRoutes(r)
 
-	return r
+	return appServices{
+		user:        userService,
+		team:        teamService,
+		pullRequest: pullService,
+		statistic:   statisticService,
+		sla:         slaService,
+		trManager:   trManager,
+	}
 }
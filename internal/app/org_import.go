@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/L11D/avito-review-assign-service/internal/config"
+	"github.com/L11D/avito-review-assign-service/internal/orgchart"
+)
+
+// RunOrgImport reads an org chart file and applies it to the database. See
+// the orgchart package for the file formats.
+func RunOrgImport(args []string) error {
+	flags := flag.NewFlagSet("orgimport", flag.ContinueOnError)
+	filePath := flags.String("file", "", "path to the org chart file (.yaml, .yml or .csv)")
+	format := flags.String("format", "", "file format, yaml or csv; detected from the extension by default")
+	dryRun := flags.Bool("dry-run", false, "print the planned changes without saving them")
+	removeAbsent := flags.Bool("remove-absent", false, "remove team members missing from the file")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *filePath == "" {
+		return errors.New("-file is required")
+	}
+
+	fileFormat := orgchart.Format(*format)
+	if fileFormat == "" {
+		fileFormat, err = orgchart.FormatFromPath(*filePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	chart, err := orgchart.Parse(file, fileFormat)
+	if err != nil {
+		return err
+	}
+
+	err = chart.Validate()
+	if err != nil {
+		return err
+	}
+
+	config, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(),
+		os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	db, err := openDB(config)
+	if err != nil {
+		return err
+	}
+
+	defer closeDB(db)
+
+	deps := initServices(db, config)
+	importer := orgchart.NewImporter(deps.team, deps.sla, deps.trManager)
+
+	reports, err := importer.Apply(ctx, chart, orgchart.Options{
+		DryRun:       *dryRun,
+		RemoveAbsent: *removeAbsent,
+	})
+	if err != nil {
+		return err
+	}
+
+	return orgchart.WriteReport(os.Stdout, reports, *dryRun)
+}
@@ -4,12 +4,16 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"strconv"
 	"time"
 )
 
 const (
 	DEFAULT_SHUTDOWN_TIMEOUT    = 10 * time.Second
 	DEFAULT_READ_HEADER_TIMEOUT = 10 * time.Second
+	DEFAULT_SLA_CHECK_INTERVAL  = time.Minute
+	DEFAULT_FALLBACK_DEPTH      = 1
+	DEFAULT_FAIRNESS_THRESHOLD  = 0.3
 )
 
 type Config struct {
@@ -21,6 +25,9 @@ type Config struct {
 	HTTPPort          string
 	ShutdownTimeout   time.Duration
 	ReadHeaderTimeout time.Duration
+	SLACheckInterval  time.Duration
+	FallbackDepth     int
+	FairnessThreshold float64
 }
 
 func getEnv(key string) (string, error) {
@@ -52,7 +59,7 @@ func getEnvDuration(key string, def time.Duration) time.Duration {
 	}
 
 	duration, err := time.ParseDuration(val)
-	if err != nil {
+	if err != nil || duration <= 0 {
 		slog.Warn("ENV " + key + " is invalid, using default " + def.String())
 
 		return def
@@ -61,6 +68,42 @@ func getEnvDuration(key string, def time.Duration) time.Duration {
 	return duration
 }
 
+func getEnvInt(key string, def int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		slog.Warn("ENV " + key + " is missing, using default " + strconv.Itoa(def))
+
+		return def
+	}
+
+	number, err := strconv.Atoi(val)
+	if err != nil || number < 0 {
+		slog.Warn("ENV " + key + " is invalid, using default " + strconv.Itoa(def))
+
+		return def
+	}
+
+	return number
+}
+
+func getEnvFloat(key string, def float64) float64 {
+	val := os.Getenv(key)
+	if val == "" {
+		slog.Warn("ENV " + key + " is missing, using default " + strconv.FormatFloat(def, 'f', -1, 64))
+
+		return def
+	}
+
+	number, err := strconv.ParseFloat(val, 64)
+	if err != nil || number < 0 {
+		slog.Warn("ENV " + key + " is invalid, using default " + strconv.FormatFloat(def, 'f', -1, 64))
+
+		return def
+	}
+
+	return number
+}
+
 func LoadConfig() (*Config, error) {
 	dbUser, err := getEnv("POSTGRES_USER")
 	if err != nil {
@@ -82,6 +125,9 @@ func LoadConfig() (*Config, error) {
 
 	httpPort := getEnvOrDefault("HTTP_PORT", "8080")
 	shutdownTimeout := getEnvDuration("SHUTDOWN_TIMEOUT", DEFAULT_SHUTDOWN_TIMEOUT)
+	slaCheckInterval := getEnvDuration("SLA_CHECK_INTERVAL", DEFAULT_SLA_CHECK_INTERVAL)
+	fallbackDepth := getEnvInt("REVIEWER_FALLBACK_DEPTH", DEFAULT_FALLBACK_DEPTH)
+	fairnessThreshold := getEnvFloat("FAIRNESS_GINI_THRESHOLD", DEFAULT_FAIRNESS_THRESHOLD)
 
 	return &Config{
 		DBUser:            dbUser,
@@ -92,6 +138,9 @@ func LoadConfig() (*Config, error) {
 		HTTPPort:          httpPort,
 		ShutdownTimeout:   shutdownTimeout,
 		ReadHeaderTimeout: DEFAULT_READ_HEADER_TIMEOUT,
+		SLACheckInterval:  slaCheckInterval,
+		FallbackDepth:     fallbackDepth,
+		FairnessThreshold: fairnessThreshold,
 	}, nil
 }
 
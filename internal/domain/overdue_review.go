@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/google/uuid"
+)
+
+// OverdueReview is a reviewer assignment on an open PR that is older than
+// the SLA of the PR team.
+type OverdueReview struct {
+	PullRequestID    string        `db:"pull_request_id"`
+	PullRequestName  string        `db:"pull_request_name"`
+	AuthorID         string        `db:"author_id"`
+	ReviewerID       string        `db:"reviewer_id"`
+	TeamID           uuid.UUID     `db:"team_id"`
+	AssignedAt       time.Time     `db:"assigned_at"`
+	Deadline         time.Time     `db:"deadline"`
+	SLABreachedAt    *time.Time    `db:"sla_breached_at"`
+	Action           dto.SLAAction `db:"action"`
+	BackupReviewerID *string       `db:"backup_reviewer_id"`
+}
@@ -1,15 +1,50 @@
 package domain
 
 import (
-	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/google/uuid"
 )
 
 type PullRequest struct {
-	ID        string       `db:"id"`
-	Name      string       `db:"name"`
-	Status    dto.PRStatus `db:"status"`
-	CreatedAt time.Time    `db:"created_at"`
-	MergedAt  *time.Time   `db:"merged_at"`
-	AuthorID  string       `db:"author_id"`
+	ID        string         `db:"id"`
+	Name      string         `db:"name"`
+	Status    dto.PRStatus   `db:"status"`
+	Priority  dto.PRPriority `db:"priority"`
+	CreatedAt time.Time      `db:"created_at"`
+	MergedAt  *time.Time     `db:"merged_at"`
+	AuthorID  string         `db:"author_id"`
+	TeamID    uuid.NullUUID  `db:"team_id"`
+}
+
+// ReviewQueueItem is an open assignment of a reviewer together with the SLA
+// deadline of the PR team, if the team has a policy.
+type ReviewQueueItem struct {
+	PullRequestID   string             `db:"pull_request_id"`
+	PullRequestName string             `db:"pull_request_name"`
+	AuthorID        string             `db:"author_id"`
+	Priority        dto.PRPriority     `db:"priority"`
+	CreatedAt       time.Time          `db:"created_at"`
+	AssignedAt      time.Time          `db:"assigned_at"`
+	Deadline        *time.Time         `db:"deadline"`
+	Verdict         *dto.ReviewVerdict `db:"verdict"`
+}
+
+// PullRequestFilter selects a page of pull requests ordered by creation time.
+// Zero values leave the corresponding condition out; After continues a listing
+// right behind the given pull request.
+type PullRequestFilter struct {
+	Status      dto.PRStatus
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Descending  bool
+	After       *PullRequestCursor
+	Limit       int
+}
+
+// PullRequestCursor is the position of a pull request in a listing.
+type PullRequestCursor struct {
+	CreatedAt time.Time
+	ID        string
 }
@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"time"
+)
+
+type PullRequestDecline struct {
+	PullRequestID string    `db:"pull_request_id"`
+	UserID        string    `db:"user_id"`
+	Reason        string    `db:"reason"`
+	DeclinedAt    time.Time `db:"declined_at"`
+}
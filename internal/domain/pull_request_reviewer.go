@@ -1,6 +1,16 @@
 package domain
 
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
 type PullRequestReviewer struct {
-	PullRequestID string `db:"pull_request_id"`
-	UserID        string `db:"user_id"`
+	PullRequestID string             `db:"pull_request_id"`
+	UserID        string             `db:"user_id"`
+	AssignedAt    time.Time          `db:"assigned_at"`
+	SLABreachedAt *time.Time         `db:"sla_breached_at"`
+	Verdict       *dto.ReviewVerdict `db:"verdict"`
+	VerdictAt     *time.Time         `db:"verdict_at"`
 }
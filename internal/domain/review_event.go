@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/google/uuid"
+)
+
+// ReviewEvent records a reviewer being assigned to or removed from a PR, or
+// the PR being merged while they review it. TeamID is the PR team at the time
+// of the event.
+type ReviewEvent struct {
+	ID            int64               `db:"id"`
+	Type          dto.ReviewEventType `db:"event_type"`
+	PullRequestID string              `db:"pull_request_id"`
+	UserID        string              `db:"user_id"`
+	TeamID        uuid.NullUUID       `db:"team_id"`
+	CreatedAt     time.Time           `db:"created_at"`
+}
+
+func NewReviewEvent(eventType dto.ReviewEventType, prId string, userId string) ReviewEvent {
+	return ReviewEvent{
+		Type:          eventType,
+		PullRequestID: prId,
+		UserID:        userId,
+	}
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
+type ReviewSLAEvent struct {
+	ID            int64              `db:"id"`
+	PullRequestID string             `db:"pull_request_id"`
+	ReviewerID    string             `db:"reviewer_id"`
+	NewReviewerID *string            `db:"new_reviewer_id"`
+	Action        dto.SLAEventAction `db:"action"`
+	CreatedAt     time.Time          `db:"created_at"`
+}
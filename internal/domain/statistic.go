@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/google/uuid"
+)
+
+// TeamStatisticFilter narrows the team statistic to one team or to teams
+// that are not archived.
+type TeamStatisticFilter struct {
+	TeamID          uuid.NullUUID
+	IncludeArchived bool
+}
+
+// TeamStatistic holds the totals over the PRs attributed to a team, i.e. the
+// PR's team context or the author's primary team when it has none.
+type TeamStatistic struct {
+	TeamID            uuid.UUID  `db:"team_id"`
+	TeamName          string     `db:"team_name"`
+	ArchivedAt        *time.Time `db:"archived_at"`
+	PRsCreated        int        `db:"prs_created"`
+	PRsMerged         int        `db:"prs_merged"`
+	PRsOpen           int        `db:"prs_open"`
+	AvgReviewersPerPR float64    `db:"avg_reviewers_per_pr"`
+	ActiveMembers     int        `db:"active_members"`
+}
+
+type TeamMemberStatistic struct {
+	UserID          string       `db:"user_id"`
+	Username        string       `db:"username"`
+	Role            dto.TeamRole `db:"role"`
+	IsActive        bool         `db:"is_active"`
+	AssignRate      int          `db:"assign_rate"`
+	PRsCreated      int          `db:"prs_created"`
+	PRsMerged       int          `db:"prs_merged"`
+	PRsOpen         int          `db:"prs_open"`
+	ReviewsAssigned int          `db:"reviews_assigned"`
+	OpenReviews     int          `db:"open_reviews"`
+}
+
+// UserStatisticFilter selects the period of the user activity totals. Without
+// activity users are ranked by assign_rate only.
+type UserStatisticFilter struct {
+	From         *time.Time
+	To           *time.Time
+	WithActivity bool
+}
+
+type UserStatistic struct {
+	UserID     string `db:"user_id"`
+	AssignRate int    `db:"assign_rate"`
+	Assigned   int    `db:"assigned"`
+	Unassigned int    `db:"unassigned"`
+	Merged     int    `db:"merged"`
+}
+
+// ActivityFilter selects review events for activity statistics. Events are
+// keyed by user or, with ByTeam set, by the PR team at the time of the event.
+// An empty GroupBy sums up the whole period.
+type ActivityFilter struct {
+	From    *time.Time
+	To      *time.Time
+	GroupBy dto.StatisticGroupBy
+	ByTeam  bool
+	UserIDs []string
+	TeamID  uuid.NullUUID
+}
+
+type ActivityPoint struct {
+	Key         string     `db:"key"`
+	PeriodStart *time.Time `db:"period_start"`
+	Assigned    int        `db:"assigned"`
+	Unassigned  int        `db:"unassigned"`
+	Merged      int        `db:"merged"`
+}
+
+type LifecycleGrouping int
+
+const (
+	LifecycleOverall LifecycleGrouping = iota
+	LifecycleByTeam
+	LifecycleByAuthor
+)
+
+// LifecycleFilter selects PRs created in [From, To) for lifecycle statistics.
+type LifecycleFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Grouping LifecycleGrouping
+}
+
+// LifecycleStatistic describes the PRs of one group. Merge times are in
+// seconds and are nil when no PR of the group is merged. Staffing is only
+// known for PRs created after it started being recorded.
+type LifecycleStatistic struct {
+	Key              string   `db:"key"`
+	PRsCreated       int      `db:"prs_created"`
+	PRsMerged        int      `db:"prs_merged"`
+	AvgMergeSeconds  *float64 `db:"avg_merge_seconds"`
+	P50MergeSeconds  *float64 `db:"p50_merge_seconds"`
+	P90MergeSeconds  *float64 `db:"p90_merge_seconds"`
+	P95MergeSeconds  *float64 `db:"p95_merge_seconds"`
+	Reassignments    int      `db:"reassignments"`
+	MaxReassignments int      `db:"max_reassignments"`
+	PRsWithStaffing  int      `db:"prs_with_staffing"`
+	UnderstaffedPRs  int      `db:"understaffed_prs"`
+}
+
+// ReviewLoad is the number of reviews a team member was assigned on the PRs
+// of that team in a period.
+type ReviewLoad struct {
+	TeamID   uuid.UUID `db:"team_id"`
+	TeamName string    `db:"team_name"`
+	UserID   string    `db:"user_id"`
+	Load     int       `db:"load"`
+}
+
+// ReviewGraphFilter selects the reviewer assignments of the review graph:
+// made between From and To on PRs of the team, if set.
+type ReviewGraphFilter struct {
+	TeamID uuid.NullUUID
+	From   *time.Time
+	To     *time.Time
+}
+
+// ReviewEdge counts the PRs of the author the reviewer is assigned to.
+type ReviewEdge struct {
+	ReviewerID   string `db:"reviewer_id"`
+	ReviewerName string `db:"reviewer_name"`
+	AuthorID     string `db:"author_id"`
+	AuthorName   string `db:"author_name"`
+	Reviews      int    `db:"reviews"`
+}
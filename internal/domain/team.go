@@ -1,10 +1,32 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type Team struct {
-	ID   uuid.UUID `db:"id"`
-	Name string    `db:"name"`
+	ID         uuid.UUID     `db:"id"`
+	Name       string        `db:"name"`
+	ArchivedAt *time.Time    `db:"archived_at"`
+	ParentID   uuid.NullUUID `db:"parent_id"`
+}
+
+// TeamFilter selects a page of teams for listings. Archived teams are only
+// included when IncludeArchived is set.
+type TeamFilter struct {
+	NamePrefix      string
+	IncludeArchived bool
+	Limit           int
+	Offset          int
+}
+
+// TeamSummary is a team with the counts of its primary members.
+type TeamSummary struct {
+	ID                 uuid.UUID  `db:"id"`
+	Name               string     `db:"name"`
+	ArchivedAt         *time.Time `db:"archived_at"`
+	MembersCount       int        `db:"members_count"`
+	ActiveMembersCount int        `db:"active_members_count"`
 }
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/google/uuid"
+)
+
+// TeamMembership links a user to a team other than their primary one.
+type TeamMembership struct {
+	UserID   string       `db:"user_id"`
+	TeamID   uuid.UUID    `db:"team_id"`
+	Role     dto.TeamRole `db:"role"`
+	JoinedAt time.Time    `db:"joined_at"`
+}
+
+// TeamMember is a user drawn from a team's reviewer pool, either through the
+// primary team or an additional membership. Role is the role in that team.
+type TeamMember struct {
+	User
+
+	PoolTeamID uuid.UUID `db:"pool_team_id"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TeamMembershipChange struct {
+	ID         int64         `db:"id"`
+	UserID     string        `db:"user_id"`
+	FromTeamID uuid.NullUUID `db:"from_team_id"`
+	ToTeamID   uuid.NullUUID `db:"to_team_id"`
+	ChangedAt  time.Time     `db:"changed_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/google/uuid"
+)
+
+type TeamSettings struct {
+	TeamID         uuid.UUID            `db:"team_id"`
+	ReviewersCount int                  `db:"reviewers_count"`
+	Strategy       dto.ReviewerStrategy `db:"strategy"`
+	OwnTeamRule    dto.OwnTeamRule      `db:"own_team_rule"`
+	UpdatedAt      *time.Time           `db:"updated_at"`
+}
+
+// TeamSettingsPatch holds the settings a request changes, nil fields are
+// kept as they are.
+type TeamSettingsPatch struct {
+	TeamID         uuid.UUID
+	ReviewersCount *int
+	Strategy       *dto.ReviewerStrategy
+	OwnTeamRule    *dto.OwnTeamRule
+}
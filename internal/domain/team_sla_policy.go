@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/google/uuid"
+)
+
+type TeamSLAPolicy struct {
+	TeamID           uuid.UUID     `db:"team_id"`
+	ReviewSLASeconds int64         `db:"review_sla_seconds"`
+	Action           dto.SLAAction `db:"action"`
+	BackupReviewerID *string       `db:"backup_reviewer_id"`
+}
+
+func (p TeamSLAPolicy) ReviewSLA() time.Duration {
+	return time.Duration(p.ReviewSLASeconds) * time.Second
+}
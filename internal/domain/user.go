@@ -1,13 +1,20 @@
 package domain
 
 import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	"github.com/google/uuid"
 )
 
 type User struct {
-	ID         string    `db:"id"`
-	Username   string    `db:"username"`
-	IsActive   bool      `db:"is_active"`
-	TeamID     uuid.UUID `db:"team_id"`
-	AssignRate int       `db:"assign_rate"`
+	ID           string        `db:"id"`
+	Username     string        `db:"username"`
+	IsActive     bool          `db:"is_active"`
+	TeamID       uuid.NullUUID `db:"team_id"`
+	AssignRate   int           `db:"assign_rate"`
+	Role         dto.TeamRole  `db:"role"`
+	Email        *string       `db:"email"`
+	FullName     *string       `db:"full_name"`
+	AnonymizedAt *time.Time    `db:"anonymized_at"`
 }
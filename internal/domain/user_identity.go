@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
+type UserIdentity struct {
+	Provider   dto.IdentityProvider `db:"provider"`
+	ExternalID string               `db:"external_id"`
+	UserID     string               `db:"user_id"`
+	CreatedAt  time.Time            `db:"created_at"`
+}
@@ -12,6 +12,12 @@ const (
 	NOT_FOUND           ErrorCode = "NOT_FOUND"
 	VALIDATION_FAILED   ErrorCode = "VALIDATION_FAILED"
 	QUERY_PARAM_MISSING ErrorCode = "QUERY_PARAM_MISSING"
+	TEAM_ARCHIVED       ErrorCode = "TEAM_ARCHIVED"
+	TEAM_IN_USE         ErrorCode = "TEAM_IN_USE"
+	FORBIDDEN           ErrorCode = "FORBIDDEN"
+	USER_ANONYMIZED     ErrorCode = "USER_ANONYMIZED"
+	IDENTITY_LINKED     ErrorCode = "IDENTITY_LINKED"
+	ALREADY_DECLINED    ErrorCode = "ALREADY_DECLINED"
 )
 
 type AppError struct {
@@ -100,6 +106,54 @@ func NewNoCandidateError() *AppError {
 	}
 }
 
+func NewTeamArchivedError(teamName string) *AppError {
+	return &AppError{
+		Code:       TEAM_ARCHIVED,
+		Message:    "Team '" + teamName + "' is archived",
+		StatusCode: 409,
+	}
+}
+
+func NewTeamInUseError(teamName string) *AppError {
+	return &AppError{
+		Code:       TEAM_IN_USE,
+		Message:    "Team '" + teamName + "' has members referenced by pull requests",
+		StatusCode: 409,
+	}
+}
+
+func NewForbiddenError(reason string) *AppError {
+	return &AppError{
+		Code:       FORBIDDEN,
+		Message:    reason,
+		StatusCode: 403,
+	}
+}
+
+func NewUserAnonymizedError(userId string) *AppError {
+	return &AppError{
+		Code:       USER_ANONYMIZED,
+		Message:    "User '" + userId + "' is anonymized",
+		StatusCode: 409,
+	}
+}
+
+func NewIdentityLinkedError(provider string, externalId string) *AppError {
+	return &AppError{
+		Code:       IDENTITY_LINKED,
+		Message:    provider + " identity '" + externalId + "' is already linked to another user",
+		StatusCode: 409,
+	}
+}
+
+func NewAlreadyDeclinedError() *AppError {
+	return &AppError{
+		Code:       ALREADY_DECLINED,
+		Message:    "Reviewer has already declined this PR",
+		StatusCode: 409,
+	}
+}
+
 func (e *AppError) Error() string {
 	return string(e.Code) + " " + e.Message
 }
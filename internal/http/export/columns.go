@@ -0,0 +1,150 @@
+package export
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// column is a CSV column taken from a field of the row type. Nested structs
+// are flattened into "parent.child" columns, embedded structs are inlined and
+// slices and maps are written as JSON.
+type column struct {
+	name  string
+	index []int
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func columnsOf(t reflect.Type, index []int, prefix string) []column {
+	t = indirectType(t)
+
+	var columns []column
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldType := indirectType(field.Type)
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			columns = append(columns, columnsOf(fieldType, fieldIndex, prefix)...)
+
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if isNested(fieldType) {
+			columns = append(columns, columnsOf(fieldType, fieldIndex, prefix+name+".")...)
+
+			continue
+		}
+
+		columns = append(columns, column{name: prefix + name, index: fieldIndex})
+	}
+
+	return columns
+}
+
+func record(row reflect.Value, columns []column) []string {
+	values := make([]string, len(columns))
+
+	for i, column := range columns {
+		field, ok := fieldByIndex(row, column.index)
+		if ok {
+			values[i] = formatValue(field)
+		}
+	}
+
+	return values
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex that reports nil pointers on the
+// way instead of panicking.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		v = reflect.Indirect(v)
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+
+		v = v.Field(i)
+	}
+
+	return v, true
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339)
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return ""
+		}
+
+		data, err := json.Marshal(v.Interface())
+		if err == nil {
+			return string(data)
+		}
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,176 @@
+// Package export writes list responses as CSV or newline delimited JSON and
+// the review graph as GraphViz DOT or GraphML. Rows are written to the client
+// as they are produced, so large exports are not collected in memory.
+package export
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatJSON   Format = "application/json"
+	FormatCSV    Format = "text/csv"
+	FormatNDJSON Format = "application/x-ndjson"
+)
+
+// flushEvery is the number of rows after which the written data is flushed
+// to the client.
+const flushEvery = 100
+
+// Negotiate picks the export format of a list from the Accept header, see
+// negotiate.
+func Negotiate(accept string) Format {
+	return negotiate(accept, []Format{FormatJSON, FormatCSV, FormatNDJSON})
+}
+
+// negotiate returns the supported media type with the highest q-value in the
+// Accept header. Types with q=0 are refused, ties go to the one listed
+// first. Without a match the result falls back to JSON.
+func negotiate(accept string, supported []Format) Format {
+	type candidate struct {
+		format Format
+		q      float64
+	}
+
+	var candidates []candidate
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil || !slices.Contains(supported, Format(mediaType)) {
+			continue
+		}
+
+		q := 1.0
+
+		if value, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			candidates = append(candidates, candidate{format: Format(mediaType), q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return FormatJSON
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	return candidates[0].format
+}
+
+// Writer writes rows of type T in CSV or NDJSON. Nothing is sent before the
+// first row or Close, so errors raised earlier can still be answered with a
+// regular error response.
+type Writer[T any] struct {
+	w       http.ResponseWriter
+	format  Format
+	csv     *csv.Writer
+	columns []column
+	started bool
+	rows    int
+}
+
+func NewWriter[T any](w http.ResponseWriter, format Format) *Writer[T] {
+	return &Writer[T]{
+		w:      w,
+		format: format,
+	}
+}
+
+// Started reports whether the response was already sent to the client.
+func (w *Writer[T]) Started() bool {
+	return w.started
+}
+
+func (w *Writer[T]) Write(row T) error {
+	err := w.start()
+	if err != nil {
+		return err
+	}
+
+	if w.format == FormatCSV {
+		err = w.csv.Write(record(reflect.ValueOf(row), w.columns))
+	} else {
+		err = json.NewEncoder(w.w).Encode(row)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%flushEvery == 0 {
+		return w.flush()
+	}
+
+	return nil
+}
+
+// Close sends the CSV header for empty results and flushes the rest.
+func (w *Writer[T]) Close() error {
+	err := w.start()
+	if err != nil {
+		return err
+	}
+
+	return w.flush()
+}
+
+func (w *Writer[T]) start() error {
+	if w.started {
+		return nil
+	}
+
+	w.started = true
+
+	w.w.Header().Set("Content-Type", string(w.format)+"; charset=utf-8")
+	w.w.WriteHeader(http.StatusOK)
+
+	if w.format != FormatCSV {
+		return nil
+	}
+
+	w.csv = csv.NewWriter(w.w)
+	w.columns = columnsOf(reflect.TypeFor[T](), nil, "")
+
+	header := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		header[i] = column.name
+	}
+
+	return w.csv.Write(header)
+}
+
+func (w *Writer[T]) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+
+		err := w.csv.Error()
+		if err != nil {
+			return err
+		}
+	}
+
+	if flusher, ok := w.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
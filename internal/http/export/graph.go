@@ -0,0 +1,142 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
+const (
+	FormatDOT     Format = "text/vnd.graphviz"
+	FormatGraphML Format = "application/graphml+xml"
+)
+
+// NegotiateGraph picks the review graph format from the Accept header, see
+// negotiate.
+func NegotiateGraph(accept string) Format {
+	return negotiate(accept, []Format{FormatJSON, FormatDOT, FormatGraphML})
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteDOT writes the review graph as a GraphViz digraph. Nodes are labelled
+// with usernames, edge weights are the review counts.
+func WriteDOT(w io.Writer, graph dto.ReviewGraphDTO) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph reviews {")
+
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(bw, "  %s [label=%s];\n", dotID(node.UserID), dotID(node.Username))
+	}
+
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(bw, "  %s -> %s [weight=%d, label=\"%d\"];\n",
+			dotID(edge.ReviewerID), dotID(edge.AuthorID), edge.Reviews, edge.Reviews)
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+func dotID(value string) string {
+	return `"` + dotEscaper.Replace(value) + `"`
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string      `xml:"id,attr"`
+	Data graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string      `xml:"source,attr"`
+	Target string      `xml:"target,attr"`
+	Data   graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the review graph as a directed GraphML graph with the
+// username of every node and the review count of every edge.
+func WriteGraphML(w io.Writer, graph dto.ReviewGraphDTO) error {
+	document := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "username", For: "node", AttrName: "username", AttrType: "string"},
+			{ID: "reviews", For: "edge", AttrName: "reviews", AttrType: "int"},
+		},
+		Graph: graphMLGraph{
+			ID:          "reviews",
+			EdgeDefault: "directed",
+			Nodes:       make([]graphMLNode, len(graph.Nodes)),
+			Edges:       make([]graphMLEdge, len(graph.Edges)),
+		},
+	}
+
+	for i, node := range graph.Nodes {
+		document.Graph.Nodes[i] = graphMLNode{
+			ID:   node.UserID,
+			Data: graphMLData{Key: "username", Value: node.Username},
+		}
+	}
+
+	for i, edge := range graph.Edges {
+		document.Graph.Edges[i] = graphMLEdge{
+			Source: edge.ReviewerID,
+			Target: edge.AuthorID,
+			Data:   graphMLData{Key: "reviews", Value: strconv.Itoa(edge.Reviews)},
+		}
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(document)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+
+	return err
+}
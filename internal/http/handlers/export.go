@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/L11D/avito-review-assign-service/internal/http/export"
+	"github.com/gin-gonic/gin"
+)
+
+// exportFormat returns the format requested in the Accept header. Handlers
+// answer with their usual JSON document for export.FormatJSON.
+func exportFormat(c *gin.Context) export.Format {
+	return export.Negotiate(c.GetHeader("Accept"))
+}
+
+// writeRows writes already loaded rows in the export format.
+func writeRows[T any](c *gin.Context, format export.Format, rows []T) {
+	streamRows(c, format, func(emit func(T) error) error {
+		for _, row := range rows {
+			err := emit(row)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// streamRows writes the rows produced by stream in the export format as they
+// come. Errors before the first row get the regular error response; later
+// the response is already sent, so the error is only logged and the client
+// gets a truncated export.
+func streamRows[T any](c *gin.Context, format export.Format, stream func(emit func(T) error) error) {
+	w := export.NewWriter[T](c.Writer, format)
+
+	err := stream(w.Write)
+	if err == nil {
+		err = w.Close()
+	}
+
+	if err == nil {
+		return
+	}
+
+	if !w.Started() {
+		c.Error(err)
+
+		return
+	}
+
+	slog.ErrorContext(c.Request.Context(), "Failed to write export",
+		slog.String("path", c.Request.URL.Path),
+		slog.String("error", err.Error()),
+	)
+	c.Abort()
+}
@@ -12,6 +12,10 @@ type PullRequestService interface {
 	Create(ctx context.Context, pr dto.PullRequestCreateDTO) (dto.PullRequestDTO, error)
 	Merge(ctx context.Context, prId string) (dto.PullRequestDTO, error)
 	Reassign(ctx context.Context, reassignDTO dto.PullRequestReassignDTO) (dto.PullRequestDTO, error)
+	Decline(ctx context.Context, declineDTO dto.PullRequestDeclineDTO) (dto.PullRequestDeclineResultDTO, error)
+	OverrideReviewer(ctx context.Context, overrideDTO dto.PullRequestOverrideReviewerDTO) (dto.PullRequestDTO, error)
+	SetPriority(ctx context.Context, priorityDTO dto.PullRequestSetPriorityDTO) (dto.PullRequestDTO, error)
+	SubmitVerdict(ctx context.Context, verdictDTO dto.PullRequestVerdictDTO) (dto.PullRequestDTO, error)
 }
 
 type PullRequestHandler struct {
@@ -29,6 +33,10 @@ func (h *PullRequestHandler) RegisterRoutes(e *gin.Engine) {
 	g.POST("/create", h.Create)
 	g.POST("/merge", h.Merge)
 	g.POST("/reassign", h.Reassign)
+	g.POST("/decline", h.Decline)
+	g.POST("/overrideReviewer", h.OverrideReviewer)
+	g.POST("/setPriority", h.SetPriority)
+	g.POST("/submitVerdict", h.SubmitVerdict)
 }
 
 func (h *PullRequestHandler) Create(c *gin.Context) {
@@ -84,3 +92,75 @@ func (h *PullRequestHandler) Reassign(c *gin.Context) {
 
 	c.JSON(200, gin.H{"pr": reassignedPR})
 }
+
+func (h *PullRequestHandler) OverrideReviewer(c *gin.Context) {
+	var dto dto.PullRequestOverrideReviewerDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	overriddenPR, err := h.service.OverrideReviewer(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, gin.H{"pr": overriddenPR})
+}
+
+func (h *PullRequestHandler) SetPriority(c *gin.Context) {
+	var dto dto.PullRequestSetPriorityDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	updatedPR, err := h.service.SetPriority(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, gin.H{"pr": updatedPR})
+}
+
+func (h *PullRequestHandler) SubmitVerdict(c *gin.Context) {
+	var dto dto.PullRequestVerdictDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	reviewedPR, err := h.service.SubmitVerdict(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, gin.H{"pr": reviewedPR})
+}
+
+func (h *PullRequestHandler) Decline(c *gin.Context) {
+	var dto dto.PullRequestDeclineDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	result, err := h.service.Decline(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, result)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/L11D/avito-review-assign-service/internal/errors"
+	"github.com/L11D/avito-review-assign-service/internal/http/export"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type SLAService interface {
+	SetTeamPolicy(ctx context.Context, policyDTO dto.TeamSLAPolicyDTO) (dto.TeamSLAPolicyDTO, error)
+	GetTeamPolicy(ctx context.Context, teamName string) (dto.TeamSLAPolicyDTO, error)
+	GetOverdueReviews(ctx context.Context, teamName string) (dto.OverdueReviewsDTO, error)
+}
+
+type SLAHandler struct {
+	service SLAService
+}
+
+func NewSLAHandler(service SLAService) *SLAHandler {
+	return &SLAHandler{
+		service: service,
+	}
+}
+
+func (h *SLAHandler) RegisterRoutes(e *gin.Engine) {
+	team := e.Group("/team")
+	team.POST("/setSla", h.SetTeamPolicy)
+	team.GET("/getSla", h.GetTeamPolicy)
+
+	pr := e.Group("/pullRequest")
+	pr.GET("/overdue", h.GetOverdueReviews)
+}
+
+func (h *SLAHandler) SetTeamPolicy(c *gin.Context) {
+	var dto dto.TeamSLAPolicyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	policy, err := h.service.SetTeamPolicy(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *SLAHandler) GetTeamPolicy(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		c.Error(errors.NewQueryParamMissingError("name"))
+
+		return
+	}
+
+	policy, err := h.service.GetTeamPolicy(c.Request.Context(), name)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *SLAHandler) GetOverdueReviews(c *gin.Context) {
+	overdueReviews, err := h.service.GetOverdueReviews(c.Request.Context(), c.Query("team_name"))
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, overdueReviews.OverdueReviews)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, overdueReviews)
+}
@@ -1,14 +1,33 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 
+	"github.com/L11D/avito-review-assign-service/internal/errors"
+	"github.com/L11D/avito-review-assign-service/internal/http/export"
 	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	"github.com/gin-gonic/gin"
 )
 
 type StatisticService interface {
-	GetUsersStatistic(ctx context.Context) (dto.AllUsersStatisticDTO, error)
+	GetUsersStatistic(ctx context.Context, query dto.StatisticPeriodQueryDTO) (dto.AllUsersStatisticDTO, error)
+	StreamUsersStatistic(
+		ctx context.Context,
+		query dto.StatisticPeriodQueryDTO,
+		emit func(dto.UserStatisticDTO) error,
+	) error
+	GetDeclinesStatistic(ctx context.Context) (dto.AllDeclinesStatisticDTO, error)
+	StreamDeclinesStatistic(ctx context.Context, emit func(dto.UserDeclineStatisticDTO) error) error
+	GetTeamsStatistic(ctx context.Context, query dto.TeamsStatisticQueryDTO) (dto.AllTeamsStatisticDTO, error)
+	GetPRLifecycleStatistic(ctx context.Context, query dto.PRLifecycleQueryDTO) (dto.AllPRLifecycleStatisticDTO, error)
+	GetFairnessStatistic(ctx context.Context, query dto.FairnessQueryDTO) (dto.AllTeamsFairnessDTO, error)
+	GetReviewGraph(ctx context.Context, query dto.ReviewGraphQueryDTO) (dto.ReviewGraphDTO, error)
+	GetTeamStatistic(
+		ctx context.Context,
+		teamName string,
+		query dto.StatisticPeriodQueryDTO,
+	) (dto.TeamDetailedStatisticDTO, error)
 }
 
 type StatisticHandler struct {
@@ -24,15 +43,240 @@ func NewStatisticHandler(service StatisticService) *StatisticHandler {
 func (h *StatisticHandler) RegisterRoutes(e *gin.Engine) {
 	g := e.Group("/statistic")
 	g.GET("/users", h.GetUsersStatistic)
+	g.GET("/declines", h.GetDeclinesStatistic)
+	g.GET("/teams", h.GetTeamsStatistic)
+	g.GET("/team", h.GetTeamStatistic)
+	g.GET("/pullRequests", h.GetPRLifecycleStatistic)
+	g.GET("/fairness", h.GetFairnessStatistic)
+	g.GET("/reviewGraph", h.GetReviewGraph)
 }
 
 func (h *StatisticHandler) GetUsersStatistic(c *gin.Context) {
-	statistic, err := h.service.GetUsersStatistic(c.Request.Context())
+	var query dto.StatisticPeriodQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		streamRows(c, format, func(emit func(dto.UserStatisticDTO) error) error {
+			return h.service.StreamUsersStatistic(c.Request.Context(), query, emit)
+		})
+
+		return
+	}
+
+	statistic, err := h.service.GetUsersStatistic(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, statistic)
+}
+
+func (h *StatisticHandler) GetDeclinesStatistic(c *gin.Context) {
+	if format := exportFormat(c); format != export.FormatJSON {
+		streamRows(c, format, func(emit func(dto.UserDeclineStatisticDTO) error) error {
+			return h.service.StreamDeclinesStatistic(c.Request.Context(), emit)
+		})
+
+		return
+	}
+
+	statistic, err := h.service.GetDeclinesStatistic(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, statistic)
+}
+
+func (h *StatisticHandler) GetTeamsStatistic(c *gin.Context) {
+	var dto dto.TeamsStatisticQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	statistic, err := h.service.GetTeamsStatistic(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, statistic.Statistics)
+
+		return
+	}
+
+	c.JSON(200, statistic)
+}
+
+func (h *StatisticHandler) GetTeamStatistic(c *gin.Context) {
+	teamName := c.Query("name")
+	if teamName == "" {
+		c.Error(errors.NewQueryParamMissingError("name"))
+
+		return
+	}
+
+	var dto dto.StatisticPeriodQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	statistic, err := h.service.GetTeamStatistic(c.Request.Context(), teamName, dto)
 	if err != nil {
 		c.Error(err)
 
 		return
 	}
 
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, statistic.Members)
+
+		return
+	}
+
 	c.JSON(200, statistic)
 }
+
+func (h *StatisticHandler) GetPRLifecycleStatistic(c *gin.Context) {
+	var dto dto.PRLifecycleQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	statistic, err := h.service.GetPRLifecycleStatistic(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, lifecycleRows(statistic))
+
+		return
+	}
+
+	c.JSON(200, statistic)
+}
+
+func (h *StatisticHandler) GetFairnessStatistic(c *gin.Context) {
+	var dto dto.FairnessQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	statistic, err := h.service.GetFairnessStatistic(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, statistic.Teams)
+
+		return
+	}
+
+	c.JSON(200, statistic)
+}
+
+func (h *StatisticHandler) GetReviewGraph(c *gin.Context) {
+	var query dto.ReviewGraphQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	graph, err := h.service.GetReviewGraph(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	var buf bytes.Buffer
+
+	format := graphFormat(c, query.Format)
+
+	switch format {
+	case export.FormatDOT:
+		err = export.WriteDOT(&buf, graph)
+	case export.FormatGraphML:
+		err = export.WriteGraphML(&buf, graph)
+	default:
+		c.JSON(200, graph)
+
+		return
+	}
+
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.Data(200, string(format)+"; charset=utf-8", buf.Bytes())
+}
+
+// graphFormat returns the review graph format requested in the Accept header.
+// The format query parameter, when given, overrides it.
+func graphFormat(c *gin.Context, override dto.ReviewGraphFormat) export.Format {
+	switch override {
+	case dto.GraphFormatJSON:
+		return export.FormatJSON
+	case dto.GraphFormatDOT:
+		return export.FormatDOT
+	case dto.GraphFormatGraphML:
+		return export.FormatGraphML
+	default:
+		return export.NegotiateGraph(c.GetHeader("Accept"))
+	}
+}
+
+// lifecycleRows flattens the lifecycle statistic into export rows: the overall
+// group first, then teams and authors.
+func lifecycleRows(statistic dto.AllPRLifecycleStatisticDTO) []dto.PRLifecycleRowDTO {
+	rows := make([]dto.PRLifecycleRowDTO, 0, 1+len(statistic.Teams)+len(statistic.Authors))
+	rows = append(rows, dto.PRLifecycleRowDTO{
+		PRLifecycleStatisticDTO: statistic.Overall,
+		Group:                   "overall",
+	})
+
+	for _, team := range statistic.Teams {
+		rows = append(rows, dto.PRLifecycleRowDTO{
+			PRLifecycleStatisticDTO: team.PRLifecycleStatisticDTO,
+			Group:                   "team",
+			Key:                     team.TeamName,
+		})
+	}
+
+	for _, author := range statistic.Authors {
+		rows = append(rows, dto.PRLifecycleRowDTO{
+			PRLifecycleStatisticDTO: author.PRLifecycleStatisticDTO,
+			Group:                   "author",
+			Key:                     author.AuthorID,
+		})
+	}
+
+	return rows
+}
@@ -3,15 +3,32 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/L11D/avito-review-assign-service/internal/errors"
+	"github.com/L11D/avito-review-assign-service/internal/http/export"
 	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	"github.com/gin-gonic/gin"
 )
 
 type TeamService interface {
 	Create(ctx context.Context, team dto.TeamDTO) (dto.TeamDTO, error)
+	Sync(ctx context.Context, syncDTO dto.TeamSyncDTO) (dto.TeamSyncResultDTO, error)
 	GetByName(ctx context.Context, name string) (dto.TeamDTO, error)
+	List(ctx context.Context, listDTO dto.TeamListQueryDTO) (dto.TeamListDTO, error)
+	DeactivateUsers(
+		ctx context.Context,
+		deactivateDTO dto.TeamDeactivateUsersDTO,
+	) (dto.TeamDeactivateUsersResultDTO, error)
+	AddMembers(ctx context.Context, addDTO dto.TeamMembersAddDTO) (dto.TeamDTO, error)
+	RemoveMembers(ctx context.Context, removeDTO dto.TeamMembersRemoveDTO) (dto.TeamMembersRemoveResultDTO, error)
+	SetMemberRole(ctx context.Context, roleDTO dto.TeamSetMemberRoleDTO) (dto.TeamMemberDTO, error)
+	Rename(ctx context.Context, renameDTO dto.TeamRenameDTO) (dto.TeamDTO, error)
+	SetParent(ctx context.Context, setParentDTO dto.TeamSetParentDTO) (dto.TeamDTO, error)
+	GetSettings(ctx context.Context, teamName string) (dto.TeamSettingsDTO, error)
+	UpdateSettings(ctx context.Context, updateDTO dto.TeamSettingsUpdateDTO) (dto.TeamSettingsDTO, error)
+	SetArchived(ctx context.Context, teamName string, archived bool) (dto.TeamArchiveStatusDTO, error)
+	Delete(ctx context.Context, teamName string) error
 }
 
 type TeamHandler struct {
@@ -27,7 +44,20 @@ func NewTeamHandler(service TeamService) *TeamHandler {
 func (h *TeamHandler) RegisterRoutes(e *gin.Engine) {
 	g := e.Group("/team")
 	g.POST("/add", h.Add)
+	g.PUT("/sync", h.Sync)
 	g.GET("/get", h.Get)
+	g.GET("/list", h.List)
+	g.POST("/deactivateUsers", h.DeactivateUsers)
+	g.POST("/addMembers", h.AddMembers)
+	g.POST("/removeMembers", h.RemoveMembers)
+	g.POST("/setMemberRole", h.SetMemberRole)
+	g.POST("/rename", h.Rename)
+	g.POST("/setParent", h.SetParent)
+	g.GET("/getSettings", h.GetSettings)
+	g.POST("/setSettings", h.UpdateSettings)
+	g.POST("/archive", h.Archive)
+	g.POST("/unarchive", h.Unarchive)
+	g.POST("/delete", h.Delete)
 }
 
 func (h *TeamHandler) Add(c *gin.Context) {
@@ -48,6 +78,24 @@ func (h *TeamHandler) Add(c *gin.Context) {
 	c.JSON(201, createdTeam)
 }
 
+func (h *TeamHandler) Sync(c *gin.Context) {
+	var dto dto.TeamSyncDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	result, err := h.service.Sync(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 func (h *TeamHandler) Get(c *gin.Context) {
 	name := c.Query("name")
 	if name == "" {
@@ -65,3 +113,216 @@ func (h *TeamHandler) Get(c *gin.Context) {
 
 	c.JSON(http.StatusOK, team)
 }
+
+func (h *TeamHandler) List(c *gin.Context) {
+	var dto dto.TeamListQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	teams, err := h.service.List(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		c.Header("X-Total-Count", strconv.Itoa(teams.Total))
+		writeRows(c, format, teams.Teams)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+}
+
+func (h *TeamHandler) DeactivateUsers(c *gin.Context) {
+	var dto dto.TeamDeactivateUsersDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	result, err := h.service.DeactivateUsers(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *TeamHandler) AddMembers(c *gin.Context) {
+	var dto dto.TeamMembersAddDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	result, err := h.service.AddMembers(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *TeamHandler) RemoveMembers(c *gin.Context) {
+	var dto dto.TeamMembersRemoveDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	result, err := h.service.RemoveMembers(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *TeamHandler) SetMemberRole(c *gin.Context) {
+	var dto dto.TeamSetMemberRoleDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	member, err := h.service.SetMemberRole(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func (h *TeamHandler) Rename(c *gin.Context) {
+	var dto dto.TeamRenameDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	result, err := h.service.Rename(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *TeamHandler) SetParent(c *gin.Context) {
+	var dto dto.TeamSetParentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	result, err := h.service.SetParent(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *TeamHandler) GetSettings(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		c.Error(errors.NewQueryParamMissingError("name"))
+
+		return
+	}
+
+	settings, err := h.service.GetSettings(c.Request.Context(), name)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *TeamHandler) UpdateSettings(c *gin.Context) {
+	var dto dto.TeamSettingsUpdateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *TeamHandler) Archive(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+func (h *TeamHandler) Unarchive(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *TeamHandler) setArchived(c *gin.Context, archived bool) {
+	var dto dto.TeamNameDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	status, err := h.service.SetArchived(c.Request.Context(), dto.TeamName, archived)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *TeamHandler) Delete(c *gin.Context) {
+	var dto dto.TeamNameDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	err := h.service.Delete(c.Request.Context(), dto.TeamName)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
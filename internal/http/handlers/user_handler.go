@@ -4,13 +4,31 @@ import (
 	"context"
 
 	"github.com/L11D/avito-review-assign-service/internal/errors"
+	"github.com/L11D/avito-review-assign-service/internal/http/export"
 	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	"github.com/gin-gonic/gin"
 )
 
 type UserService interface {
-	SetIsActive(ctx context.Context, userSetIsActiveDTO dto.UserSetIsActiveDTO) (dto.UserDTO, error)
-	GetReviews(ctx context.Context, userId string) (dto.UserPRsDTO, error)
+	SetIsActive(
+		ctx context.Context,
+		userSetIsActiveDTO dto.UserSetIsActiveDTO,
+	) (dto.UserSetIsActiveResultDTO, error)
+	GetReviews(ctx context.Context, query dto.UserPRsQueryDTO) (dto.UserPRsDTO, error)
+	GetAuthored(ctx context.Context, query dto.UserPRsQueryDTO) (dto.UserPRsDTO, error)
+	GetReviewQueue(ctx context.Context, query dto.UserReviewQueueQueryDTO) (dto.UserReviewQueueDTO, error)
+	MoveTeam(ctx context.Context, moveDTO dto.UserMoveTeamDTO) (dto.UserMoveTeamResultDTO, error)
+	GetTeamHistory(ctx context.Context, userId string) (dto.UserTeamHistoryDTO, error)
+	JoinTeam(ctx context.Context, membershipDTO dto.UserTeamMembershipDTO) (dto.UserTeamsDTO, error)
+	LeaveTeam(ctx context.Context, membershipDTO dto.UserTeamMembershipDTO) (dto.UserTeamsDTO, error)
+	GetTeams(ctx context.Context, userId string) (dto.UserTeamsDTO, error)
+	GetUser(ctx context.Context, userId string) (dto.UserDTO, error)
+	UpdateProfile(ctx context.Context, updateDTO dto.UserUpdateDTO) (dto.UserDTO, error)
+	Anonymize(ctx context.Context, userId string) (dto.UserAnonymizeResultDTO, error)
+	LinkIdentity(ctx context.Context, linkDTO dto.UserIdentityLinkDTO) (dto.UserIdentitiesDTO, error)
+	UnlinkIdentity(ctx context.Context, linkDTO dto.UserIdentityLinkDTO) (dto.UserIdentitiesDTO, error)
+	GetIdentities(ctx context.Context, userId string) (dto.UserIdentitiesDTO, error)
+	ResolveIdentity(ctx context.Context, query dto.UserResolveQueryDTO) (dto.UserDTO, error)
 }
 
 type UserHandler struct {
@@ -27,6 +45,20 @@ func (h *UserHandler) RegisterRoutes(e *gin.Engine) {
 	g := e.Group("/users")
 	g.POST("/setIsActive", h.setIsActive)
 	g.GET("/getReview", h.getReviews)
+	g.GET("/getAuthored", h.getAuthored)
+	g.GET("/reviewQueue", h.getReviewQueue)
+	g.POST("/moveTeam", h.moveTeam)
+	g.GET("/teamHistory", h.getTeamHistory)
+	g.POST("/joinTeam", h.joinTeam)
+	g.POST("/leaveTeam", h.leaveTeam)
+	g.GET("/teams", h.getTeams)
+	g.GET("/get", h.getUser)
+	g.PATCH("/update", h.updateProfile)
+	g.POST("/anonymize", h.anonymize)
+	g.POST("/linkIdentity", h.linkIdentity)
+	g.POST("/unlinkIdentity", h.unlinkIdentity)
+	g.GET("/identities", h.getIdentities)
+	g.GET("/resolve", h.resolveIdentity)
 }
 
 func (h *UserHandler) setIsActive(c *gin.Context) {
@@ -48,6 +80,98 @@ func (h *UserHandler) setIsActive(c *gin.Context) {
 }
 
 func (h *UserHandler) getReviews(c *gin.Context) {
+	var dto dto.UserPRsQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	userPRs, err := h.service.GetReviews(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		c.Header("X-Next-Cursor", userPRs.NextCursor)
+		writeRows(c, format, userPRs.PullRequests)
+
+		return
+	}
+
+	c.JSON(200, userPRs)
+}
+
+func (h *UserHandler) getAuthored(c *gin.Context) {
+	var dto dto.UserPRsQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	userPRs, err := h.service.GetAuthored(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		c.Header("X-Next-Cursor", userPRs.NextCursor)
+		writeRows(c, format, userPRs.PullRequests)
+
+		return
+	}
+
+	c.JSON(200, userPRs)
+}
+
+func (h *UserHandler) getReviewQueue(c *gin.Context) {
+	var dto dto.UserReviewQueueQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	queue, err := h.service.GetReviewQueue(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, queue.PullRequests)
+
+		return
+	}
+
+	c.JSON(200, queue)
+}
+
+func (h *UserHandler) moveTeam(c *gin.Context) {
+	var dto dto.UserMoveTeamDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	movedUser, err := h.service.MoveTeam(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, movedUser)
+}
+
+func (h *UserHandler) getTeamHistory(c *gin.Context) {
 	userId := c.Query("user_id")
 	if userId == "" {
 		c.Error(errors.NewValidationFailedError("user_id is required"))
@@ -55,12 +179,233 @@ func (h *UserHandler) getReviews(c *gin.Context) {
 		return
 	}
 
-	userPRs, err := h.service.GetReviews(c.Request.Context(), userId)
+	history, err := h.service.GetTeamHistory(c.Request.Context(), userId)
 	if err != nil {
 		c.Error(err)
 
 		return
 	}
 
-	c.JSON(200, userPRs)
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, history.Changes)
+
+		return
+	}
+
+	c.JSON(200, history)
+}
+
+func (h *UserHandler) joinTeam(c *gin.Context) {
+	var dto dto.UserTeamMembershipDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	userTeams, err := h.service.JoinTeam(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, userTeams)
+}
+
+func (h *UserHandler) leaveTeam(c *gin.Context) {
+	var dto dto.UserTeamMembershipDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	userTeams, err := h.service.LeaveTeam(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, userTeams)
+}
+
+func (h *UserHandler) getTeams(c *gin.Context) {
+	userId := c.Query("user_id")
+	if userId == "" {
+		c.Error(errors.NewValidationFailedError("user_id is required"))
+
+		return
+	}
+
+	userTeams, err := h.service.GetTeams(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, userTeamRows(userTeams))
+
+		return
+	}
+
+	c.JSON(200, userTeams)
+}
+
+// userTeamRows flattens the teams of the user into export rows, the primary
+// team first.
+func userTeamRows(userTeams dto.UserTeamsDTO) []dto.UserTeamRowDTO {
+	rows := make([]dto.UserTeamRowDTO, 0, 1+len(userTeams.AdditionalTeamNames))
+
+	if userTeams.PrimaryTeamName != "" {
+		rows = append(rows, dto.UserTeamRowDTO{
+			UserID:   userTeams.UserID,
+			TeamName: userTeams.PrimaryTeamName,
+			Primary:  true,
+		})
+	}
+
+	for _, teamName := range userTeams.AdditionalTeamNames {
+		rows = append(rows, dto.UserTeamRowDTO{
+			UserID:   userTeams.UserID,
+			TeamName: teamName,
+		})
+	}
+
+	return rows
+}
+
+func (h *UserHandler) getUser(c *gin.Context) {
+	userId := c.Query("user_id")
+	if userId == "" {
+		c.Error(errors.NewValidationFailedError("user_id is required"))
+
+		return
+	}
+
+	user, err := h.service.GetUser(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, user)
+}
+
+func (h *UserHandler) updateProfile(c *gin.Context) {
+	var dto dto.UserUpdateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	updatedUser, err := h.service.UpdateProfile(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, updatedUser)
+}
+
+func (h *UserHandler) anonymize(c *gin.Context) {
+	var dto dto.UserIDDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	anonymizedUser, err := h.service.Anonymize(c.Request.Context(), dto.UserID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, anonymizedUser)
+}
+
+func (h *UserHandler) linkIdentity(c *gin.Context) {
+	var dto dto.UserIdentityLinkDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	identities, err := h.service.LinkIdentity(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, identities)
+}
+
+func (h *UserHandler) unlinkIdentity(c *gin.Context) {
+	var dto dto.UserIdentityLinkDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	identities, err := h.service.UnlinkIdentity(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, identities)
+}
+
+func (h *UserHandler) getIdentities(c *gin.Context) {
+	userId := c.Query("user_id")
+	if userId == "" {
+		c.Error(errors.NewValidationFailedError("user_id is required"))
+
+		return
+	}
+
+	identities, err := h.service.GetIdentities(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if format := exportFormat(c); format != export.FormatJSON {
+		writeRows(c, format, identities.Identities)
+
+		return
+	}
+
+	c.JSON(200, identities)
+}
+
+func (h *UserHandler) resolveIdentity(c *gin.Context) {
+	var dto dto.UserResolveQueryDTO
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.Error(errors.NewValidationFailedError(err.Error()))
+
+		return
+	}
+
+	user, err := h.service.ResolveIdentity(c.Request.Context(), dto)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(200, user)
 }
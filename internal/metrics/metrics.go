@@ -0,0 +1,79 @@
+// Package metrics holds the Prometheus collectors of the service. They are
+// registered in the default registry and served by promhttp on /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "review_assign"
+
+// Sources of NO_CANDIDATE occurrences.
+const (
+	NoCandidateCreate   = "create"
+	NoCandidateReassign = "reassign"
+	NoCandidateDecline  = "decline"
+	NoCandidateBatch    = "batch"
+	NoCandidateSLA      = "sla"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of handled HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of database queries by repository method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"query"})
+
+	TransactionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_duration_seconds",
+		Help:      "Duration of database transactions from begin to commit or rollback.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"status"})
+
+	PullRequestsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pull_requests_created_total",
+		Help:      "Number of created pull requests.",
+	})
+
+	PullRequestsMerged = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pull_requests_merged_total",
+		Help:      "Number of merged pull requests.",
+	})
+
+	ReviewerReassignments = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviewer_reassignments_total",
+		Help:      "Number of reviewers replaced on open pull requests.",
+	})
+
+	NoCandidate = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "no_candidate_total",
+		Help:      "Number of times no reviewer candidate could be found.",
+	}, []string{"source"})
+
+	ReviewersPerPullRequest = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reviewers_per_pull_request",
+		Help:      "Number of reviewers assigned to a pull request on creation.",
+		Buckets:   prometheus.LinearBuckets(0, 1, 6),
+	})
+)
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+)
+
+// Transaction outcomes used as the status label of TransactionDuration.
+const (
+	TransactionCommitted  = "commit"
+	TransactionRolledBack = "rollback"
+	TransactionFailed     = "error"
+)
+
+// TrFactory wraps a transaction factory so the duration of every transaction
+// is reported to TransactionDuration once it is committed or rolled back.
+// Nested transactions (savepoints) are not supported by the wrapped
+// transactions, the service does not use them.
+func TrFactory(factory trm.TrFactory) trm.TrFactory {
+	return func(ctx context.Context, s trm.Settings) (context.Context, trm.Transaction, error) {
+		ctx, tr, err := factory(ctx, s)
+		if err != nil {
+			return ctx, nil, err
+		}
+
+		return ctx, &timedTransaction{tr: tr, start: time.Now()}, nil
+	}
+}
+
+// AfterCommit runs fn once the transaction carried by ctx is committed, so
+// counters never include work that was rolled back. Without a transaction fn
+// runs right away.
+func AfterCommit(ctx context.Context, fn func()) {
+	tr, ok := trmcontext.DefaultManager.Default(ctx).(*timedTransaction)
+	if !ok || !tr.IsActive() {
+		fn()
+
+		return
+	}
+
+	tr.afterCommit = append(tr.afterCommit, fn)
+}
+
+type timedTransaction struct {
+	tr          trm.Transaction
+	start       time.Time
+	afterCommit []func()
+}
+
+func (t *timedTransaction) Transaction() interface{} {
+	return t.tr.Transaction()
+}
+
+func (t *timedTransaction) Commit(ctx context.Context) error {
+	err := t.tr.Commit(ctx)
+	t.observe(TransactionCommitted, err)
+
+	if err == nil {
+		for _, fn := range t.afterCommit {
+			fn()
+		}
+	}
+
+	t.afterCommit = nil
+
+	return err
+}
+
+func (t *timedTransaction) Rollback(ctx context.Context) error {
+	err := t.tr.Rollback(ctx)
+	t.observe(TransactionRolledBack, err)
+
+	t.afterCommit = nil
+
+	return err
+}
+
+func (t *timedTransaction) IsActive() bool {
+	return t.tr.IsActive()
+}
+
+func (t *timedTransaction) Closed() <-chan struct{} {
+	return t.tr.Closed()
+}
+
+func (t *timedTransaction) observe(status string, err error) {
+	if err != nil {
+		status = TransactionFailed
+	}
+
+	TransactionDuration.WithLabelValues(status).Observe(time.Since(t.start).Seconds())
+}
@@ -0,0 +1,312 @@
+package orgchart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	appErrors "github.com/L11D/avito-review-assign-service/internal/errors"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+)
+
+var errDryRun = errors.New("dry run")
+
+type TeamService interface {
+	Sync(ctx context.Context, syncDTO dto.TeamSyncDTO) (dto.TeamSyncResultDTO, error)
+	SetParent(ctx context.Context, setParentDTO dto.TeamSetParentDTO) (dto.TeamDTO, error)
+	GetSettings(ctx context.Context, teamName string) (dto.TeamSettingsDTO, error)
+	UpdateSettings(ctx context.Context, updateDTO dto.TeamSettingsUpdateDTO) (dto.TeamSettingsDTO, error)
+}
+
+type SLAService interface {
+	GetTeamPolicy(ctx context.Context, teamName string) (dto.TeamSLAPolicyDTO, error)
+	SetTeamPolicy(ctx context.Context, policyDTO dto.TeamSLAPolicyDTO) (dto.TeamSLAPolicyDTO, error)
+}
+
+type Options struct {
+	DryRun       bool
+	RemoveAbsent bool
+}
+
+type TeamReport struct {
+	Name            string
+	Sync            dto.TeamSyncResultDTO
+	ParentChanged   bool
+	OldParentName   string
+	NewParentName   string
+	SLAChanged      bool
+	SettingsChanged bool
+}
+
+type Importer struct {
+	teamService TeamService
+	slaService  SLAService
+	trManager   *manager.Manager
+}
+
+func NewImporter(teamService TeamService, slaService SLAService, trManager *manager.Manager) *Importer {
+	return &Importer{
+		teamService: teamService,
+		slaService:  slaService,
+		trManager:   trManager,
+	}
+}
+
+// Apply validates the chart and applies it in a single transaction. In dry
+// run mode the transaction is rolled back after every change was made, so
+// the report shows exactly what a real run would do.
+func (i *Importer) Apply(ctx context.Context, chart OrgChart, opts Options) ([]TeamReport, error) {
+	err := chart.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := chart.orderedTeams()
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []TeamReport
+
+	err = i.trManager.Do(ctx, func(ctx context.Context) error {
+		reports = make([]TeamReport, 0, len(teams))
+
+		for _, team := range teams {
+			report, err := i.applyTeam(ctx, team, opts.RemoveAbsent)
+			if err != nil {
+				return fmt.Errorf("team '%s': %w", team.Name, err)
+			}
+
+			reports = append(reports, report)
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func (i *Importer) applyTeam(ctx context.Context, team Team, removeAbsent bool) (TeamReport, error) {
+	members := make([]dto.TeamMemberDTO, len(team.Members))
+	for j, member := range team.Members {
+		isActive := true
+		if member.IsActive != nil {
+			isActive = *member.IsActive
+		}
+
+		members[j] = dto.TeamMemberDTO{
+			ID:       member.ID,
+			Username: member.Username,
+			IsActive: &isActive,
+			Role:     member.Role,
+		}
+	}
+
+	syncResult, err := i.teamService.Sync(ctx, dto.TeamSyncDTO{
+		Name:         team.Name,
+		Members:      members,
+		RemoveAbsent: removeAbsent,
+	})
+	if err != nil {
+		return TeamReport{}, err
+	}
+
+	report := TeamReport{
+		Name: team.Name,
+		Sync: syncResult,
+	}
+
+	if syncResult.Team.ParentTeamName != nil {
+		report.OldParentName = *syncResult.Team.ParentTeamName
+	}
+
+	if report.OldParentName != team.ParentName {
+		report.NewParentName = team.ParentName
+
+		setParentDTO := dto.TeamSetParentDTO{TeamName: team.Name}
+		if team.ParentName != "" {
+			setParentDTO.ParentTeamName = &team.ParentName
+		}
+
+		_, err = i.teamService.SetParent(ctx, setParentDTO)
+		if err != nil {
+			return TeamReport{}, err
+		}
+
+		report.ParentChanged = true
+	}
+
+	if team.Settings != nil && team.Settings.hasSLA() {
+		report.SLAChanged, err = i.applySLA(ctx, team.Name, *team.Settings)
+		if err != nil {
+			return TeamReport{}, err
+		}
+	}
+
+	if team.Settings != nil && team.Settings.hasAssignment() {
+		report.SettingsChanged, err = i.applyAssignment(ctx, team.Name, *team.Settings)
+		if err != nil {
+			return TeamReport{}, err
+		}
+	}
+
+	return report, nil
+}
+
+func (i *Importer) applySLA(ctx context.Context, teamName string, settings Settings) (bool, error) {
+	policy := dto.TeamSLAPolicyDTO{
+		TeamName:  teamName,
+		ReviewSLA: settings.ReviewSLA,
+		Action:    settings.SLAAction,
+	}
+
+	if settings.BackupReviewerID != "" {
+		policy.BackupReviewerID = &settings.BackupReviewerID
+	}
+
+	current, err := i.slaService.GetTeamPolicy(ctx, teamName)
+	if err == nil && samePolicy(current, policy) {
+		return false, nil
+	}
+
+	var appErr *appErrors.AppError
+	if err != nil && (!errors.As(err, &appErr) || appErr.Code != appErrors.NOT_FOUND) {
+		return false, err
+	}
+
+	_, err = i.slaService.SetTeamPolicy(ctx, policy)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// applyAssignment updates only the assignment settings given in the file
+// and reports whether any of them differed from the stored ones.
+func (i *Importer) applyAssignment(ctx context.Context, teamName string, settings Settings) (bool, error) {
+	current, err := i.teamService.GetSettings(ctx, teamName)
+	if err != nil {
+		return false, err
+	}
+
+	updateDTO := dto.TeamSettingsUpdateDTO{TeamName: teamName}
+	changed := false
+
+	if settings.ReviewersCount != 0 && settings.ReviewersCount != current.ReviewersCount {
+		updateDTO.ReviewersCount = &settings.ReviewersCount
+		changed = true
+	}
+
+	if settings.Strategy != "" && settings.Strategy != current.Strategy {
+		updateDTO.Strategy = &settings.Strategy
+		changed = true
+	}
+
+	if settings.OwnTeamRule != "" && settings.OwnTeamRule != current.OwnTeamRule {
+		updateDTO.OwnTeamRule = &settings.OwnTeamRule
+		changed = true
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	_, err = i.teamService.UpdateSettings(ctx, updateDTO)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func samePolicy(current dto.TeamSLAPolicyDTO, desired dto.TeamSLAPolicyDTO) bool {
+	currentSLA, _ := time.ParseDuration(current.ReviewSLA)
+	desiredSLA, _ := time.ParseDuration(desired.ReviewSLA)
+
+	currentBackup, desiredBackup := "", ""
+	if current.BackupReviewerID != nil {
+		currentBackup = *current.BackupReviewerID
+	}
+
+	if desired.BackupReviewerID != nil {
+		desiredBackup = *desired.BackupReviewerID
+	}
+
+	return currentSLA == desiredSLA && current.Action == desired.Action && currentBackup == desiredBackup
+}
+
+// WriteReport prints the changes of every team in a human readable form.
+func WriteReport(w io.Writer, reports []TeamReport, dryRun bool) error {
+	if dryRun {
+		_, err := fmt.Fprintln(w, "Dry run, nothing was saved. Planned changes:")
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, report := range reports {
+		changes := report.Sync.Changes
+
+		status := "unchanged"
+		if report.Sync.TeamCreated {
+			status = "created"
+		} else if report.ParentChanged || report.SLAChanged || report.SettingsChanged ||
+			len(changes.Added)+len(changes.Moved)+
+				len(changes.Updated)+len(changes.Removed) > 0 {
+			status = "updated"
+		}
+
+		lines := []string{fmt.Sprintf("team %s: %s", report.Name, status)}
+
+		for _, change := range []struct {
+			name    string
+			userIds []string
+		}{
+			{"added", changes.Added},
+			{"moved in", changes.Moved},
+			{"updated", changes.Updated},
+			{"removed", changes.Removed},
+		} {
+			for _, userId := range change.userIds {
+				lines = append(lines, fmt.Sprintf("  %s user %s", change.name, userId))
+			}
+		}
+
+		if report.ParentChanged {
+			lines = append(lines, fmt.Sprintf("  parent team '%s' -> '%s'", report.OldParentName, report.NewParentName))
+		}
+
+		if report.SLAChanged {
+			lines = append(lines, "  SLA policy updated")
+		}
+
+		if report.SettingsChanged {
+			lines = append(lines, "  reviewer settings updated")
+		}
+
+		if changes.Reassignment != nil {
+			lines = append(lines, fmt.Sprintf("  reviews reassigned: %d, left without candidate: %d",
+				len(changes.Reassignment.Reassigned), len(changes.Reassignment.NoCandidate)))
+		}
+
+		for _, line := range lines {
+			_, err := fmt.Fprintln(w, line)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
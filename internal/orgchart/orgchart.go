@@ -0,0 +1,230 @@
+package orgchart
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
+const (
+	maxNameLength     = 50
+	maxReviewersCount = 5
+)
+
+// OrgChart is the desired state of teams and their rosters read from an
+// import file.
+type OrgChart struct {
+	Teams []Team `yaml:"teams"`
+}
+
+type Team struct {
+	Name       string    `yaml:"name"`
+	ParentName string    `yaml:"parent"`
+	Settings   *Settings `yaml:"settings"`
+	Members    []Member  `yaml:"members"`
+}
+
+// Member is a primary team member. IsActive defaults to true and Role to
+// member when omitted.
+type Member struct {
+	ID       string       `yaml:"id"`
+	Username string       `yaml:"username"`
+	IsActive *bool        `yaml:"is_active"`
+	Role     dto.TeamRole `yaml:"role"`
+}
+
+// Settings holds the team SLA policy and reviewer assignment settings. Both
+// groups are optional: the SLA policy is replaced only when review_sla or
+// sla_action is given, and each omitted assignment setting keeps its current
+// value.
+type Settings struct {
+	ReviewSLA        string               `yaml:"review_sla"`
+	SLAAction        dto.SLAAction        `yaml:"sla_action"`
+	BackupReviewerID string               `yaml:"backup_reviewer_id"`
+	ReviewersCount   int                  `yaml:"reviewers_count"`
+	Strategy         dto.ReviewerStrategy `yaml:"strategy"`
+	OwnTeamRule      dto.OwnTeamRule      `yaml:"own_team_rule"`
+}
+
+// Validate checks the whole chart and reports every problem at once.
+func (c OrgChart) Validate() error {
+	var errs []error
+
+	if len(c.Teams) == 0 {
+		errs = append(errs, errors.New("file contains no teams"))
+	}
+
+	teamNames := make(map[string]bool, len(c.Teams))
+	memberTeams := make(map[string]string)
+
+	for _, team := range c.Teams {
+		errs = append(errs, validateName("team name", team.Name)...)
+
+		if teamNames[team.Name] {
+			errs = append(errs, fmt.Errorf("team '%s' is listed more than once", team.Name))
+		}
+
+		teamNames[team.Name] = true
+
+		if team.ParentName != "" {
+			errs = append(errs, validateName("parent of team '"+team.Name+"'", team.ParentName)...)
+		}
+
+		if team.ParentName == team.Name {
+			errs = append(errs, fmt.Errorf("team '%s' cannot be its own parent", team.Name))
+		}
+
+		if team.Settings != nil {
+			errs = append(errs, team.Settings.validate(team.Name)...)
+		}
+
+		for _, member := range team.Members {
+			errs = append(errs, validateName("user id in team '"+team.Name+"'", member.ID)...)
+
+			if member.Username == "" {
+				errs = append(errs, fmt.Errorf("user '%s' in team '%s' has no username", member.ID, team.Name))
+			}
+
+			switch member.Role {
+			case "", dto.TeamRoleLead, dto.TeamRoleMember, dto.TeamRoleObserver:
+			default:
+				errs = append(errs, fmt.Errorf(
+					"user '%s' in team '%s' has unknown role '%s'", member.ID, team.Name, member.Role,
+				))
+			}
+
+			if otherTeam, ok := memberTeams[member.ID]; ok {
+				errs = append(errs, fmt.Errorf(
+					"user '%s' is listed in team '%s' and team '%s'", member.ID, otherTeam, team.Name,
+				))
+			}
+
+			memberTeams[member.ID] = team.Name
+		}
+	}
+
+	if _, err := c.orderedTeams(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// orderedTeams returns the teams with every parent listed in the file placed
+// before its children.
+func (c OrgChart) orderedTeams() ([]Team, error) {
+	teamsByName := make(map[string]Team, len(c.Teams))
+	for _, team := range c.Teams {
+		teamsByName[team.Name] = team
+	}
+
+	ordered := make([]Team, 0, len(c.Teams))
+	state := make(map[string]int, len(c.Teams))
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	var visit func(team Team) error
+	visit = func(team Team) error {
+		switch state[team.Name] {
+		case visiting:
+			return fmt.Errorf("team '%s' is part of a parent cycle", team.Name)
+		case visited:
+			return nil
+		}
+
+		state[team.Name] = visiting
+
+		if parent, ok := teamsByName[team.ParentName]; ok {
+			err := visit(parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		state[team.Name] = visited
+		ordered = append(ordered, team)
+
+		return nil
+	}
+
+	for _, team := range c.Teams {
+		err := visit(team)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+func (s Settings) hasSLA() bool {
+	return s.ReviewSLA != "" || s.SLAAction != "" || s.BackupReviewerID != ""
+}
+
+func (s Settings) hasAssignment() bool {
+	return s.ReviewersCount != 0 || s.Strategy != "" || s.OwnTeamRule != ""
+}
+
+func (s Settings) validate(teamName string) []error {
+	var errs []error
+
+	if s.hasSLA() {
+		errs = append(errs, s.validateSLA(teamName)...)
+	}
+
+	if s.ReviewersCount < 0 || s.ReviewersCount > maxReviewersCount {
+		errs = append(errs, fmt.Errorf(
+			"reviewers_count of team '%s' must be 1 to %d, got %d", teamName, maxReviewersCount, s.ReviewersCount,
+		))
+	}
+
+	switch s.Strategy {
+	case "", dto.ReviewerStrategyLeastLoaded, dto.ReviewerStrategyRandom:
+	default:
+		errs = append(errs, fmt.Errorf("strategy of team '%s' must be LEAST_LOADED or RANDOM", teamName))
+	}
+
+	switch s.OwnTeamRule {
+	case "", dto.OwnTeamPreferred, dto.OwnTeamOnly, dto.OwnTeamExcluded:
+	default:
+		errs = append(errs, fmt.Errorf("own_team_rule of team '%s' must be PREFERRED, ONLY or EXCLUDED", teamName))
+	}
+
+	return errs
+}
+
+func (s Settings) validateSLA(teamName string) []error {
+	var errs []error
+
+	reviewSLA, err := time.ParseDuration(s.ReviewSLA)
+	if err != nil || reviewSLA < time.Second {
+		errs = append(errs, fmt.Errorf(
+			"review_sla of team '%s' must be a duration of at least 1s, got '%s'", teamName, s.ReviewSLA,
+		))
+	}
+
+	switch s.SLAAction {
+	case dto.SLAActionReassign:
+	case dto.SLAActionEscalate:
+		if s.BackupReviewerID == "" {
+			errs = append(errs, fmt.Errorf("team '%s' needs backup_reviewer_id for the ESCALATE action", teamName))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("sla_action of team '%s' must be REASSIGN or ESCALATE", teamName))
+	}
+
+	return errs
+}
+
+func validateName(field string, value string) []error {
+	if value == "" || len(value) > maxNameLength {
+		return []error{fmt.Errorf("%s must be 1 to %d characters long, got '%s'", field, maxNameLength, value)}
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package orgchart
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/goccy/go-yaml"
+)
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatCSV  Format = "csv"
+)
+
+var (
+	requiredCSVColumns = []string{"team_name", "user_id", "username"}
+	optionalCSVColumns = []string{
+		"is_active", "role", "parent_team_name", "review_sla", "sla_action", "backup_reviewer_id",
+		"reviewers_count", "strategy", "own_team_rule",
+	}
+)
+
+// FormatFromPath guesses the file format from its extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".csv":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("cannot detect format of '%s', use .yaml, .yml or .csv", path)
+	}
+}
+
+func Parse(r io.Reader, format Format) (OrgChart, error) {
+	switch format {
+	case FormatYAML:
+		return parseYAML(r)
+	case FormatCSV:
+		return parseCSV(r)
+	default:
+		return OrgChart{}, fmt.Errorf("unsupported format '%s'", format)
+	}
+}
+
+func parseYAML(r io.Reader) (OrgChart, error) {
+	var chart OrgChart
+
+	err := yaml.NewDecoder(r, yaml.DisallowUnknownField()).Decode(&chart)
+	if err != nil {
+		return OrgChart{}, err
+	}
+
+	return chart, nil
+}
+
+// parseCSV reads one member per row. Team level columns may be repeated on
+// every row of the team but must not contradict each other. A row with an
+// empty user_id declares a team without adding a member.
+func parseCSV(r io.Reader) (OrgChart, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return OrgChart{}, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if !slices.Contains(requiredCSVColumns, column) && !slices.Contains(optionalCSVColumns, column) {
+			return OrgChart{}, fmt.Errorf("unknown CSV column '%s'", column)
+		}
+
+		columns[column] = i
+	}
+
+	for _, column := range requiredCSVColumns {
+		if _, ok := columns[column]; !ok {
+			return OrgChart{}, fmt.Errorf("missing CSV column '%s'", column)
+		}
+	}
+
+	var (
+		chart       OrgChart
+		errs        []error
+		teamIndexes = make(map[string]int)
+	)
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return OrgChart{}, err
+		}
+
+		field := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		teamName := field("team_name")
+
+		i, ok := teamIndexes[teamName]
+		if !ok {
+			i = len(chart.Teams)
+			teamIndexes[teamName] = i
+			chart.Teams = append(chart.Teams, Team{Name: teamName})
+		}
+
+		team := &chart.Teams[i]
+
+		settings := Settings{
+			ReviewSLA:        field("review_sla"),
+			SLAAction:        dto.SLAAction(field("sla_action")),
+			BackupReviewerID: field("backup_reviewer_id"),
+			Strategy:         dto.ReviewerStrategy(field("strategy")),
+			OwnTeamRule:      dto.OwnTeamRule(field("own_team_rule")),
+		}
+
+		if reviewersCount := field("reviewers_count"); reviewersCount != "" {
+			settings.ReviewersCount, err = strconv.Atoi(reviewersCount)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: reviewers_count must be a number, got '%s'", line, reviewersCount))
+			}
+		}
+
+		err = mergeCSVTeamFields(team, field("parent_team_name"), settings)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+		}
+
+		if field("user_id") == "" {
+			continue
+		}
+
+		member := Member{
+			ID:       field("user_id"),
+			Username: field("username"),
+			Role:     dto.TeamRole(field("role")),
+		}
+
+		if isActive := field("is_active"); isActive != "" {
+			value, err := strconv.ParseBool(isActive)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: is_active must be true or false, got '%s'", line, isActive))
+			}
+
+			member.IsActive = &value
+		}
+
+		team.Members = append(team.Members, member)
+	}
+
+	if len(errs) > 0 {
+		return OrgChart{}, errors.Join(errs...)
+	}
+
+	return chart, nil
+}
+
+func mergeCSVTeamFields(team *Team, parentName string, settings Settings) error {
+	if parentName != "" {
+		if team.ParentName != "" && team.ParentName != parentName {
+			return fmt.Errorf("team '%s' has conflicting parent teams", team.Name)
+		}
+
+		team.ParentName = parentName
+	}
+
+	if settings == (Settings{}) {
+		return nil
+	}
+
+	if team.Settings != nil && *team.Settings != settings {
+		return fmt.Errorf("team '%s' has conflicting settings", team.Name)
+	}
+
+	team.Settings = &settings
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package orgchart
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+)
+
+const validYAML = `
+teams:
+  - name: platform
+    settings:
+      reviewers_count: 1
+      strategy: RANDOM
+    members:
+      - id: u1
+        username: Alice
+        role: lead
+  - name: backend
+    parent: platform
+    settings:
+      review_sla: 24h
+      sla_action: REASSIGN
+      own_team_rule: ONLY
+    members:
+      - id: u2
+        username: Bob
+        is_active: false
+`
+
+const validCSV = "team_name,user_id,username,is_active,role,parent_team_name," +
+	"review_sla,sla_action,reviewers_count,strategy,own_team_rule\n" +
+	"platform,u1,Alice,,lead,,,,1,RANDOM,\n" +
+	"backend,u2,Bob,false,,platform,24h,REASSIGN,,,ONLY\n" +
+	"backend,,,,,platform,24h,REASSIGN,,,ONLY\n"
+
+func expectedChart() OrgChart {
+	inactive := false
+
+	return OrgChart{
+		Teams: []Team{
+			{
+				Name:     "platform",
+				Settings: &Settings{ReviewersCount: 1, Strategy: dto.ReviewerStrategyRandom},
+				Members:  []Member{{ID: "u1", Username: "Alice", Role: dto.TeamRoleLead}},
+			},
+			{
+				Name:       "backend",
+				ParentName: "platform",
+				Settings: &Settings{
+					ReviewSLA:   "24h",
+					SLAAction:   dto.SLAActionReassign,
+					OwnTeamRule: dto.OwnTeamOnly,
+				},
+				Members: []Member{{ID: "u2", Username: "Bob", IsActive: &inactive}},
+			},
+		},
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		format Format
+		input  string
+	}{
+		{FormatYAML, validYAML},
+		{FormatCSV, validCSV},
+	} {
+		t.Run(string(tc.format), func(t *testing.T) {
+			chart, err := Parse(strings.NewReader(tc.input), tc.format)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if !reflect.DeepEqual(chart, expectedChart()) {
+				t.Fatalf("parsed chart does not match expected values: %+v", chart)
+			}
+
+			err = chart.Validate()
+			if err != nil {
+				t.Fatalf("expected a valid chart, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format Format
+		input  string
+		errMsg string
+	}{
+		{
+			name:   "unknown yaml field",
+			format: FormatYAML,
+			input:  "teams:\n  - name: platform\n    owner: u1\n",
+			errMsg: "owner",
+		},
+		{
+			name:   "unknown csv column",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,owner\nplatform,u1,Alice,u1\n",
+			errMsg: "unknown CSV column 'owner'",
+		},
+		{
+			name:   "missing csv column",
+			format: FormatCSV,
+			input:  "team_name,user_id\nplatform,u1\n",
+			errMsg: "missing CSV column 'username'",
+		},
+		{
+			name:   "invalid is_active",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,is_active\nplatform,u1,Alice,maybe\n",
+			errMsg: "line 2: is_active must be true or false",
+		},
+		{
+			name:   "invalid reviewers_count",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,reviewers_count\nplatform,u1,Alice,two\n",
+			errMsg: "line 2: reviewers_count must be a number",
+		},
+		{
+			name:   "conflicting parents",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,parent_team_name\nbackend,u1,Alice,platform\nbackend,u2,Bob,infra\n",
+			errMsg: "line 3: team 'backend' has conflicting parent teams",
+		},
+		{
+			name:   "conflicting settings",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,strategy\nbackend,u1,Alice,RANDOM\nbackend,u2,Bob,LEAST_LOADED\n",
+			errMsg: "line 3: team 'backend' has conflicting settings",
+		},
+		{
+			name:   "unsupported format",
+			format: Format("xml"),
+			input:  "<teams/>",
+			errMsg: "unsupported format 'xml'",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.input), tc.format)
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Fatalf("expected error containing %q, got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format Format
+		input  string
+		errMsg []string
+	}{
+		{
+			name:   "empty file",
+			format: FormatYAML,
+			input:  "teams: []\n",
+			errMsg: []string{"file contains no teams"},
+		},
+		{
+			name:   "duplicate team and member",
+			format: FormatYAML,
+			input: `
+teams:
+  - name: platform
+    members:
+      - id: u1
+        username: Alice
+  - name: platform
+    members:
+      - id: u1
+        username: Alice
+`,
+			errMsg: []string{
+				"team 'platform' is listed more than once",
+				"user 'u1' is listed in team 'platform' and team 'platform'",
+			},
+		},
+		{
+			name:   "parent cycle",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,parent_team_name\nbackend,u1,Alice,platform\nplatform,u2,Bob,backend\n",
+			errMsg: []string{"is part of a parent cycle"},
+		},
+		{
+			name:   "own parent",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,parent_team_name\nbackend,u1,Alice,backend\n",
+			errMsg: []string{"team 'backend' cannot be its own parent"},
+		},
+		{
+			name:   "member fields",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,role\nbackend,u1,,owner\n",
+			errMsg: []string{
+				"user 'u1' in team 'backend' has no username",
+				"user 'u1' in team 'backend' has unknown role 'owner'",
+			},
+		},
+		{
+			name:   "sla policy",
+			format: FormatYAML,
+			input: `
+teams:
+  - name: backend
+    settings:
+      review_sla: 10ms
+      sla_action: ESCALATE
+`,
+			errMsg: []string{
+				"review_sla of team 'backend' must be a duration of at least 1s",
+				"team 'backend' needs backup_reviewer_id for the ESCALATE action",
+			},
+		},
+		{
+			name:   "assignment settings",
+			format: FormatCSV,
+			input:  "team_name,user_id,username,reviewers_count,strategy,own_team_rule\nbackend,,,6,ROUND_ROBIN,ALWAYS\n",
+			errMsg: []string{
+				"reviewers_count of team 'backend' must be 1 to 5, got 6",
+				"strategy of team 'backend' must be LEAST_LOADED or RANDOM",
+				"own_team_rule of team 'backend' must be PREFERRED, ONLY or EXCLUDED",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			chart, err := Parse(strings.NewReader(tc.input), tc.format)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			err = chart.Validate()
+			if err == nil {
+				t.Fatalf("expected validation errors %q, got none", tc.errMsg)
+			}
+
+			for _, errMsg := range tc.errMsg {
+				if !strings.Contains(err.Error(), errMsg) {
+					t.Fatalf("expected error containing %q, got %v", errMsg, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_AssignmentSettingsWithoutSLA(t *testing.T) {
+	chart := OrgChart{
+		Teams: []Team{{Name: "backend", Settings: &Settings{ReviewersCount: 3}}},
+	}
+
+	err := chart.Validate()
+	if err != nil {
+		t.Fatalf("expected settings without an SLA policy to be valid, got %v", err)
+	}
+}
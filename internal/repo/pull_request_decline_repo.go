@@ -0,0 +1,125 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type pullRequestDeclineRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewPullRequestDeclineRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *pullRequestDeclineRepo {
+	return &pullRequestDeclineRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+func (r *pullRequestDeclineRepo) Save(
+	ctx context.Context,
+	decline domain.PullRequestDecline,
+) (domain.PullRequestDecline, error) {
+	query := r.qb.
+		Insert("pull_request_declines").
+		Columns("pull_request_id", "user_id", "reason").
+		Values(decline.PullRequestID, decline.UserID, decline.Reason).
+		Suffix("RETURNING pull_request_id, user_id, reason, declined_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.PullRequestDecline{}, err
+	}
+
+	var createdDecline domain.PullRequestDecline
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestDeclineRepo.Save")
+
+	err = tr.GetContext(ctx, &createdDecline, sql, args...)
+	if err != nil {
+		return domain.PullRequestDecline{}, err
+	}
+
+	return createdDecline, nil
+}
+
+func (r *pullRequestDeclineRepo) GetPRUsersIds(ctx context.Context, prId string) ([]string, error) {
+	query := r.qb.
+		Select("user_id").
+		From("pull_request_declines").
+		Where(sq.Eq{"pull_request_id": prId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var usersIds []string
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestDeclineRepo.GetPRUsersIds")
+
+	err = tr.SelectContext(ctx, &usersIds, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return usersIds, nil
+}
+
+func (r *pullRequestDeclineRepo) GetByPRIds(ctx context.Context, prIds []string) ([]domain.PullRequestDecline, error) {
+	query := r.qb.
+		Select("pull_request_id", "user_id", "reason", "declined_at").
+		From("pull_request_declines").
+		Where("pull_request_id = ANY(?)", pq.Array(prIds))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var declines []domain.PullRequestDecline
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestDeclineRepo.GetByPRIds")
+
+	err = tr.SelectContext(ctx, &declines, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return declines, nil
+}
+
+// EachGroupedByUser passes all declines to fn grouped by user: users with more
+// declines go first, ties are broken by the most recent decline. Declines of a
+// user are ordered from the newest.
+func (r *pullRequestDeclineRepo) EachGroupedByUser(
+	ctx context.Context,
+	fn func(domain.PullRequestDecline) error,
+) error {
+	query := r.qb.
+		Select("pull_request_id", "user_id", "reason", "declined_at").
+		From("pull_request_declines").
+		OrderBy(
+			"COUNT(*) OVER (PARTITION BY user_id) DESC",
+			"MAX(declined_at) OVER (PARTITION BY user_id) DESC",
+			"user_id",
+			"declined_at DESC",
+		)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestDeclineRepo.EachGroupedByUser")
+
+	return eachRow(ctx, tr, sql, args, fn)
+}
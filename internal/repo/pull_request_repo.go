@@ -4,9 +4,11 @@ import (
 	"context"
 
 	"github.com/L11D/avito-review-assign-service/internal/domain"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	sq "github.com/Masterminds/squirrel"
 	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type pullRequestRepo struct {
@@ -26,9 +28,9 @@ func NewPullRequestRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *pullRequestRepo
 func (r *pullRequestRepo) Save(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error) {
 	query := r.qb.
 		Insert("pull_requests").
-		Columns("id", "name", "author_id").
-		Values(pr.ID, pr.Name, pr.AuthorID).
-		Suffix("RETURNING id, name, status, created_at, merged_at, author_id")
+		Columns("id", "name", "author_id", "team_id", "priority").
+		Values(pr.ID, pr.Name, pr.AuthorID, pr.TeamID, pr.Priority).
+		Suffix("RETURNING id, name, status, created_at, merged_at, author_id, team_id, priority")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -37,7 +39,9 @@ func (r *pullRequestRepo) Save(ctx context.Context, pr domain.PullRequest) (doma
 
 	var createdPR domain.PullRequest
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &createdPR, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.Save")
+
+	err = tr.GetContext(ctx, &createdPR, sql, args...)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -45,12 +49,164 @@ func (r *pullRequestRepo) Save(ctx context.Context, pr domain.PullRequest) (doma
 	return createdPR, nil
 }
 
-func (r *pullRequestRepo) GetByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
-	query := r.qb.
-		Select("id", "name", "status", "created_at", "merged_at", "author_id").
+// GetByUserId returns the pull requests the user reviews, narrowed and paged
+// by the filter.
+func (r *pullRequestRepo) GetByUserId(
+	ctx context.Context,
+	userId string,
+	filter domain.PullRequestFilter,
+) ([]domain.PullRequest, error) {
+	query := r.applyPRFilter(r.qb.
+		Select("id", "name", "status", "created_at", "merged_at", "author_id", "team_id", "priority").
 		From("pull_requests").
 		Join("pull_request_reviewers prr ON pull_requests.id = prr.pull_request_id").
-		Where(sq.Eq{"prr.user_id": userId})
+		Where(sq.Eq{"prr.user_id": userId}), filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []domain.PullRequest
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.GetByUserId")
+
+	err = tr.SelectContext(ctx, &prs, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// GetReviewQueue returns the open assignments of the reviewer, most urgent
+// first: by PR priority, then by the SLA deadline of the PR team and then by
+// PR age. Assignments the reviewer already gave a verdict on are skipped
+// unless includeReviewed is set.
+func (r *pullRequestRepo) GetReviewQueue(
+	ctx context.Context,
+	userId string,
+	includeReviewed bool,
+) ([]domain.ReviewQueueItem, error) {
+	query := r.qb.
+		Select(
+			"pr.id AS pull_request_id",
+			"pr.name AS pull_request_name",
+			"pr.author_id",
+			"pr.priority",
+			"pr.created_at",
+			"prr.assigned_at",
+			"prr.assigned_at + p.review_sla_seconds * INTERVAL '1 second' AS deadline",
+			"prr.verdict",
+		).
+		From("pull_request_reviewers prr").
+		Join("pull_requests pr ON pr.id = prr.pull_request_id").
+		Join("users a ON a.id = pr.author_id").
+		LeftJoin("team_sla_policies p ON p.team_id = COALESCE(pr.team_id, a.team_id)").
+		Where(sq.Eq{"prr.user_id": userId, "pr.status": dto.StatusOpen}).
+		OrderBy(
+			"array_position(ARRAY['URGENT', 'HIGH', 'NORMAL', 'LOW'], pr.priority::text)",
+			"deadline NULLS LAST",
+			"pr.created_at",
+			"pr.id",
+		)
+
+	if !includeReviewed {
+		query = query.Where(sq.Eq{"prr.verdict": nil})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []domain.ReviewQueueItem
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.GetReviewQueue")
+
+	err = tr.SelectContext(ctx, &items, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *pullRequestRepo) GetByAuthorId(
+	ctx context.Context,
+	authorId string,
+	filter domain.PullRequestFilter,
+) ([]domain.PullRequest, error) {
+	query := r.applyPRFilter(r.qb.
+		Select("id", "name", "status", "created_at", "merged_at", "author_id", "team_id", "priority").
+		From("pull_requests").
+		Where(sq.Eq{"author_id": authorId}), filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []domain.PullRequest
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.GetByAuthorId")
+
+	err = tr.SelectContext(ctx, &prs, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// applyPRFilter adds the filter conditions and a keyset on (created_at, id),
+// so later pages stay stable while new pull requests are created.
+func (r *pullRequestRepo) applyPRFilter(query sq.SelectBuilder, filter domain.PullRequestFilter) sq.SelectBuilder {
+	if filter.Status != "" {
+		query = query.Where(sq.Eq{"pull_requests.status": filter.Status})
+	}
+
+	if filter.CreatedFrom != nil {
+		query = query.Where(sq.GtOrEq{"pull_requests.created_at": *filter.CreatedFrom})
+	}
+
+	if filter.CreatedTo != nil {
+		query = query.Where(sq.Lt{"pull_requests.created_at": *filter.CreatedTo})
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		query = query.Where(
+			"(pull_requests.created_at, pull_requests.id) "+comparison+" (?, ?)",
+			filter.After.CreatedAt,
+			filter.After.ID,
+		)
+	}
+
+	query = query.OrderBy("pull_requests.created_at "+direction, "pull_requests.id "+direction)
+
+	if filter.Limit > 0 {
+		query = query.Limit(uint64(filter.Limit))
+	}
+
+	return query
+}
+
+func (r *pullRequestRepo) GetOpenByUserIds(ctx context.Context, userIds []string) ([]domain.PullRequest, error) {
+	query := r.qb.
+		Select("id", "name", "status", "created_at", "merged_at", "author_id", "team_id", "priority").
+		From("pull_requests").
+		Where(sq.Eq{"status": dto.StatusOpen}).
+		Where(sq.Expr(
+			"EXISTS (SELECT 1 FROM pull_request_reviewers prr "+
+				"WHERE prr.pull_request_id = pull_requests.id AND prr.user_id = ANY(?))",
+			pq.Array(userIds),
+		)).
+		OrderBy("created_at", "id")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -59,7 +215,9 @@ func (r *pullRequestRepo) GetByUserId(ctx context.Context, userId string) ([]dom
 
 	var prs []domain.PullRequest
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).SelectContext(ctx, &prs, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.GetOpenByUserIds")
+
+	err = tr.SelectContext(ctx, &prs, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +227,7 @@ func (r *pullRequestRepo) GetByUserId(ctx context.Context, userId string) ([]dom
 
 func (r *pullRequestRepo) GetByID(ctx context.Context, prId string) (domain.PullRequest, error) {
 	query := r.qb.
-		Select("id", "name", "status", "created_at", "merged_at", "author_id").
+		Select("id", "name", "status", "created_at", "merged_at", "author_id", "team_id", "priority").
 		From("pull_requests").
 		Where(sq.Eq{"id": prId})
 
@@ -80,7 +238,35 @@ func (r *pullRequestRepo) GetByID(ctx context.Context, prId string) (domain.Pull
 
 	var pr domain.PullRequest
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &pr, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.GetByID")
+
+	err = tr.GetContext(ctx, &pr, sql, args...)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return pr, nil
+}
+
+// GetByIDForUpdate reads the PR and locks its row until the surrounding
+// transaction ends.
+func (r *pullRequestRepo) GetByIDForUpdate(ctx context.Context, prId string) (domain.PullRequest, error) {
+	query := r.qb.
+		Select("id", "name", "status", "created_at", "merged_at", "author_id", "team_id", "priority").
+		From("pull_requests").
+		Where(sq.Eq{"id": prId}).
+		Suffix("FOR UPDATE")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	var pr domain.PullRequest
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.GetByIDForUpdate")
+
+	err = tr.GetContext(ctx, &pr, sql, args...)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -88,14 +274,39 @@ func (r *pullRequestRepo) GetByID(ctx context.Context, prId string) (domain.Pull
 	return pr, nil
 }
 
+// SetStaffing stores how many reviewers the PR team required when the PR was
+// created and how many of them could actually be assigned.
+func (r *pullRequestRepo) SetStaffing(ctx context.Context, prId string, required int, initial int) error {
+	query := r.qb.
+		Update("pull_requests").
+		Set("required_reviewers", required).
+		Set("initial_reviewers", initial).
+		Where(sq.Eq{"id": prId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.SetStaffing")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *pullRequestRepo) Update(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error) {
 	query := r.qb.
 		Update("pull_requests").
 		Set("name", pr.Name).
 		Set("status", pr.Status).
 		Set("merged_at", pr.MergedAt).
+		Set("priority", pr.Priority).
 		Where(sq.Eq{"id": pr.ID}).
-		Suffix("RETURNING id, name, status, created_at, merged_at, author_id")
+		Suffix("RETURNING id, name, status, created_at, merged_at, author_id, team_id, priority")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -104,7 +315,9 @@ func (r *pullRequestRepo) Update(ctx context.Context, pr domain.PullRequest) (do
 
 	var updatedPR domain.PullRequest
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &updatedPR, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestRepo.Update")
+
+	err = tr.GetContext(ctx, &updatedPR, sql, args...)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
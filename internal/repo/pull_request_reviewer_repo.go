@@ -2,13 +2,20 @@ package repo
 
 import (
 	"context"
+	"slices"
 
 	"github.com/L11D/avito-review-assign-service/internal/domain"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	sq "github.com/Masterminds/squirrel"
 	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// Keeps every batch statement well below the PostgreSQL bind parameter limit.
+const reviewersBatchSize = 1000
+
 type pullRequestReviewerRepo struct {
 	db     *sqlx.DB
 	qb     sq.StatementBuilderType
@@ -40,7 +47,9 @@ func (r *pullRequestReviewerRepo) Save(
 
 	var createdPRReviewer domain.PullRequestReviewer
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &createdPRReviewer, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.Save")
+
+	err = tr.GetContext(ctx, &createdPRReviewer, sql, args...)
 	if err != nil {
 		return domain.PullRequestReviewer{}, err
 	}
@@ -61,7 +70,9 @@ func (r *pullRequestReviewerRepo) GetPRUsersIds(ctx context.Context, prId string
 
 	var usersIds []string
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).SelectContext(ctx, &usersIds, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.GetPRUsersIds")
+
+	err = tr.SelectContext(ctx, &usersIds, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +90,200 @@ func (r *pullRequestReviewerRepo) DeleteByPRAndUserId(ctx context.Context, prId
 		return err
 	}
 
-	_, err = r.getter.DefaultTrOrDB(ctx, r.db).ExecContext(ctx, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.DeleteByPRAndUserId")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *pullRequestReviewerRepo) GetByPRIds(
+	ctx context.Context,
+	prIds []string,
+) ([]domain.PullRequestReviewer, error) {
+	query := r.qb.
+		Select("pull_request_id", "user_id", "assigned_at", "verdict", "verdict_at").
+		From("pull_request_reviewers").
+		Where("pull_request_id = ANY(?)", pq.Array(prIds)).
+		OrderBy("assigned_at", "user_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var prReviewers []domain.PullRequestReviewer
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.GetByPRIds")
+
+	err = tr.SelectContext(ctx, &prReviewers, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return prReviewers, nil
+}
+
+func (r *pullRequestReviewerRepo) SaveBatch(ctx context.Context, prReviewers []domain.PullRequestReviewer) error {
+	for batch := range slices.Chunk(prReviewers, reviewersBatchSize) {
+		query := r.qb.
+			Insert("pull_request_reviewers").
+			Columns("pull_request_id", "user_id")
+
+		for _, prReviewer := range batch {
+			query = query.Values(prReviewer.PullRequestID, prReviewer.UserID)
+		}
+
+		sql, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+
+		tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.SaveBatch")
+
+		_, err = tr.ExecContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *pullRequestReviewerRepo) DeleteBatch(ctx context.Context, prReviewers []domain.PullRequestReviewer) error {
+	for batch := range slices.Chunk(prReviewers, reviewersBatchSize) {
+		prIds := make([]string, len(batch))
+		userIds := make([]string, len(batch))
+
+		for i, prReviewer := range batch {
+			prIds[i] = prReviewer.PullRequestID
+			userIds[i] = prReviewer.UserID
+		}
+
+		query := r.qb.
+			Delete("pull_request_reviewers").
+			Where(
+				"(pull_request_id, user_id) IN (SELECT * FROM UNNEST(?::varchar[], ?::varchar[]))",
+				pq.Array(prIds),
+				pq.Array(userIds),
+			)
+
+		sql, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+
+		tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.DeleteBatch")
+
+		_, err = tr.ExecContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetOverdue returns assignments on open PRs that outlived the SLA of the PR
+// team, falling back to the author's team for PRs without one. Reviewers who
+// already submitted a verdict are never overdue. With onlyUnhandled set,
+// assignments already marked as breached are skipped.
+func (r *pullRequestReviewerRepo) GetOverdue(
+	ctx context.Context,
+	teamId *uuid.UUID,
+	onlyUnhandled bool,
+) ([]domain.OverdueReview, error) {
+	query := r.qb.
+		Select(
+			"prr.pull_request_id",
+			"pr.name AS pull_request_name",
+			"pr.author_id",
+			"prr.user_id AS reviewer_id",
+			"p.team_id",
+			"prr.assigned_at",
+			"prr.assigned_at + p.review_sla_seconds * INTERVAL '1 second' AS deadline",
+			"prr.sla_breached_at",
+			"p.action",
+			"p.backup_reviewer_id",
+		).
+		From("pull_request_reviewers prr").
+		Join("pull_requests pr ON pr.id = prr.pull_request_id").
+		Join("users a ON a.id = pr.author_id").
+		Join("team_sla_policies p ON p.team_id = COALESCE(pr.team_id, a.team_id)").
+		Where(sq.Eq{"pr.status": dto.StatusOpen}).
+		Where("prr.assigned_at + p.review_sla_seconds * INTERVAL '1 second' < NOW()").
+		Where(sq.Eq{"prr.verdict": nil}).
+		OrderBy("deadline")
+
+	if teamId != nil {
+		query = query.Where(sq.Eq{"p.team_id": *teamId})
+	}
+
+	if onlyUnhandled {
+		query = query.Where(sq.Eq{"prr.sla_breached_at": nil})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var overdueReviews []domain.OverdueReview
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.GetOverdue")
+
+	err = tr.SelectContext(ctx, &overdueReviews, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return overdueReviews, nil
+}
+
+func (r *pullRequestReviewerRepo) SetVerdict(
+	ctx context.Context,
+	prId string,
+	userId string,
+	verdict dto.ReviewVerdict,
+) error {
+	query := r.qb.
+		Update("pull_request_reviewers").
+		Set("verdict", verdict).
+		Set("verdict_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"pull_request_id": prId, "user_id": userId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.SetVerdict")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *pullRequestReviewerRepo) MarkSLABreached(ctx context.Context, prId string, userId string) error {
+	query := r.qb.
+		Update("pull_request_reviewers").
+		Set("sla_breached_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"pull_request_id": prId, "user_id": userId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "pullRequestReviewerRepo.MarkSLABreached")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"slices"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// prTeamExpr resolves the team a PR is attributed to at the time of the event.
+const prTeamExpr = "(SELECT COALESCE(pr.team_id, a.team_id) FROM pull_requests pr " +
+	"JOIN users a ON a.id = pr.author_id WHERE pr.id = ?)"
+
+type reviewEventRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewReviewEventRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *reviewEventRepo {
+	return &reviewEventRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+func (r *reviewEventRepo) SaveBatch(ctx context.Context, events []domain.ReviewEvent) error {
+	for batch := range slices.Chunk(events, reviewersBatchSize) {
+		query := r.qb.
+			Insert("review_events").
+			Columns("event_type", "pull_request_id", "user_id", "team_id")
+
+		for _, event := range batch {
+			query = query.Values(event.Type, event.PullRequestID, event.UserID, sq.Expr(prTeamExpr, event.PullRequestID))
+		}
+
+		sql, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+
+		tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "reviewEventRepo.SaveBatch")
+
+		_, err = tr.ExecContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type reviewSLAEventRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewReviewSLAEventRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *reviewSLAEventRepo {
+	return &reviewSLAEventRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+func (r *reviewSLAEventRepo) Save(ctx context.Context, event domain.ReviewSLAEvent) (domain.ReviewSLAEvent, error) {
+	query := r.qb.
+		Insert("review_sla_events").
+		Columns("pull_request_id", "reviewer_id", "new_reviewer_id", "action").
+		Values(event.PullRequestID, event.ReviewerID, event.NewReviewerID, event.Action).
+		Suffix("RETURNING id, pull_request_id, reviewer_id, new_reviewer_id, action, created_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.ReviewSLAEvent{}, err
+	}
+
+	var createdEvent domain.ReviewSLAEvent
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "reviewSLAEventRepo.Save")
+
+	err = tr.GetContext(ctx, &createdEvent, sql, args...)
+	if err != nil {
+		return domain.ReviewSLAEvent{}, err
+	}
+
+	return createdEvent, nil
+}
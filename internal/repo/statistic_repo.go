@@ -0,0 +1,462 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type statisticRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewStatisticRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *statisticRepo {
+	return &statisticRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+// GetTeamsStatistic aggregates PR totals per team. A PR counts towards its
+// team context or, when it has none, towards the author's primary team.
+// Active members include additional memberships.
+func (r *statisticRepo) GetTeamsStatistic(
+	ctx context.Context,
+	filter domain.TeamStatisticFilter,
+) ([]domain.TeamStatistic, error) {
+	query := r.qb.
+		Select(
+			"t.id AS team_id",
+			"t.name AS team_name",
+			"t.archived_at",
+			"COALESCE(p.prs_created, 0) AS prs_created",
+			"COALESCE(p.prs_merged, 0) AS prs_merged",
+			"COALESCE(p.prs_open, 0) AS prs_open",
+			"COALESCE(p.avg_reviewers_per_pr, 0) AS avg_reviewers_per_pr",
+			"COALESCE(m.active_members, 0) AS active_members",
+		).
+		From("teams t").
+		LeftJoin(
+			"(SELECT COALESCE(pr.team_id, a.team_id) AS team_id, "+
+				"COUNT(*) AS prs_created, "+
+				"COUNT(*) FILTER (WHERE pr.status = ?) AS prs_merged, "+
+				"COUNT(*) FILTER (WHERE pr.status = ?) AS prs_open, "+
+				"ROUND(AVG(COALESCE(rc.reviewers_count, 0)), 2) AS avg_reviewers_per_pr "+
+				"FROM pull_requests pr "+
+				"JOIN users a ON a.id = pr.author_id "+
+				"LEFT JOIN (SELECT pull_request_id, COUNT(*) AS reviewers_count "+
+				"FROM pull_request_reviewers GROUP BY pull_request_id) rc ON rc.pull_request_id = pr.id "+
+				"GROUP BY 1) p ON p.team_id = t.id",
+			dto.StatusMerged,
+			dto.StatusOpen,
+		).
+		LeftJoin(
+			"(SELECT mu.team_id, COUNT(*) AS active_members FROM (" +
+				"SELECT id AS user_id, team_id FROM users WHERE team_id IS NOT NULL AND is_active " +
+				"UNION SELECT tm.user_id, tm.team_id FROM team_memberships tm " +
+				"JOIN users u ON u.id = tm.user_id WHERE u.is_active" +
+				") mu GROUP BY mu.team_id) m ON m.team_id = t.id",
+		).
+		OrderBy("t.name")
+
+	if filter.TeamID.Valid {
+		query = query.Where(sq.Eq{"t.id": filter.TeamID.UUID})
+	}
+
+	if !filter.IncludeArchived {
+		query = query.Where(sq.Eq{"t.archived_at": nil})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var statistics []domain.TeamStatistic
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "statisticRepo.GetTeamsStatistic")
+
+	err = tr.SelectContext(ctx, &statistics, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return statistics, nil
+}
+
+// GetTeamMembersStatistic breaks the team down by its primary and additional
+// members. Authored PRs and reviews are counted across all teams.
+func (r *statisticRepo) GetTeamMembersStatistic(
+	ctx context.Context,
+	teamId uuid.UUID,
+) ([]domain.TeamMemberStatistic, error) {
+	query := r.qb.
+		Select(
+			"u.id AS user_id",
+			"u.username",
+			"m.role",
+			"u.is_active",
+			"u.assign_rate",
+			"COALESCE(a.prs_created, 0) AS prs_created",
+			"COALESCE(a.prs_merged, 0) AS prs_merged",
+			"COALESCE(a.prs_open, 0) AS prs_open",
+			"COALESCE(rv.reviews_assigned, 0) AS reviews_assigned",
+			"COALESCE(rv.open_reviews, 0) AS open_reviews",
+		).
+		Prefix(
+			"WITH members AS (SELECT id AS user_id, role FROM users WHERE team_id = ? "+
+				"UNION SELECT user_id, role FROM team_memberships WHERE team_id = ?)",
+			teamId,
+			teamId,
+		).
+		From("members m").
+		Join("users u ON u.id = m.user_id").
+		LeftJoin(
+			"(SELECT author_id, "+
+				"COUNT(*) AS prs_created, "+
+				"COUNT(*) FILTER (WHERE status = ?) AS prs_merged, "+
+				"COUNT(*) FILTER (WHERE status = ?) AS prs_open "+
+				"FROM pull_requests WHERE author_id IN (SELECT user_id FROM members) "+
+				"GROUP BY author_id) a ON a.author_id = u.id",
+			dto.StatusMerged,
+			dto.StatusOpen,
+		).
+		LeftJoin(
+			"(SELECT prr.user_id, "+
+				"COUNT(*) AS reviews_assigned, "+
+				"COUNT(*) FILTER (WHERE pr.status = ?) AS open_reviews "+
+				"FROM pull_request_reviewers prr "+
+				"JOIN pull_requests pr ON pr.id = prr.pull_request_id "+
+				"WHERE prr.user_id IN (SELECT user_id FROM members) "+
+				"GROUP BY prr.user_id) rv ON rv.user_id = u.id",
+			dto.StatusOpen,
+		).
+		OrderBy("u.id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var statistics []domain.TeamMemberStatistic
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "statisticRepo.GetTeamMembersStatistic")
+
+	err = tr.SelectContext(ctx, &statistics, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return statistics, nil
+}
+
+// EachUserStatistic passes every user to fn ranked by assign_rate or, with
+// activity, by merged and then assigned reviews in the period.
+func (r *statisticRepo) EachUserStatistic(
+	ctx context.Context,
+	filter domain.UserStatisticFilter,
+	fn func(domain.UserStatistic) error,
+) error {
+	query := r.qb.
+		Select("u.id AS user_id", "u.assign_rate").
+		From("users u")
+
+	if filter.WithActivity {
+		events := sq.
+			Select("user_id").
+			Column("COUNT(*) FILTER (WHERE event_type = ?) AS assigned", dto.EventAssigned).
+			Column("COUNT(*) FILTER (WHERE event_type = ?) AS unassigned", dto.EventUnassigned).
+			Column("COUNT(*) FILTER (WHERE event_type = ?) AS merged", dto.EventMerged).
+			From("review_events").
+			GroupBy("user_id")
+
+		if filter.From != nil {
+			events = events.Where(sq.GtOrEq{"created_at": *filter.From})
+		}
+
+		if filter.To != nil {
+			events = events.Where(sq.Lt{"created_at": *filter.To})
+		}
+
+		eventsSql, eventsArgs, err := events.ToSql()
+		if err != nil {
+			return err
+		}
+
+		query = query.
+			Columns(
+				"COALESCE(e.assigned, 0) AS assigned",
+				"COALESCE(e.unassigned, 0) AS unassigned",
+				"COALESCE(e.merged, 0) AS merged",
+			).
+			LeftJoin("("+eventsSql+") e ON e.user_id = u.id", eventsArgs...).
+			OrderBy("merged DESC", "assigned DESC", "u.id")
+	} else {
+		query = query.OrderBy("u.assign_rate DESC", "u.id")
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "statisticRepo.EachUserStatistic")
+
+	return eachRow(ctx, tr, sql, args, fn)
+}
+
+// GetActivity counts review events per user or per team. Merges are counted
+// per reviewer for users and once per PR for teams. Periods start at UTC
+// midnight, weeks on Monday.
+func (r *statisticRepo) GetActivity(ctx context.Context, filter domain.ActivityFilter) ([]domain.ActivityPoint, error) {
+	keyColumn := "e.user_id"
+	mergedColumn := "COUNT(*) FILTER (WHERE e.event_type = ?) AS merged"
+
+	if filter.ByTeam {
+		keyColumn = "e.team_id::text"
+		mergedColumn = "COUNT(DISTINCT e.pull_request_id) FILTER (WHERE e.event_type = ?) AS merged"
+	}
+
+	query := r.qb.
+		Select(keyColumn+" AS key").
+		Column("COUNT(*) FILTER (WHERE e.event_type = ?) AS assigned", dto.EventAssigned).
+		Column("COUNT(*) FILTER (WHERE e.event_type = ?) AS unassigned", dto.EventUnassigned).
+		Column(mergedColumn, dto.EventMerged).
+		From("review_events e").
+		GroupBy("key").
+		OrderBy("key")
+
+	if filter.GroupBy != "" {
+		query = query.
+			Column("date_trunc(?, e.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS period_start", filter.GroupBy).
+			GroupBy("period_start").
+			OrderBy("period_start")
+	} else {
+		query = query.Column("NULL::timestamptz AS period_start")
+	}
+
+	if filter.ByTeam {
+		query = query.Where(sq.NotEq{"e.team_id": nil})
+	}
+
+	if filter.From != nil {
+		query = query.Where(sq.GtOrEq{"e.created_at": *filter.From})
+	}
+
+	if filter.To != nil {
+		query = query.Where(sq.Lt{"e.created_at": *filter.To})
+	}
+
+	if filter.UserIDs != nil {
+		query = query.Where("e.user_id = ANY(?)", pq.Array(filter.UserIDs))
+	}
+
+	if filter.TeamID.Valid {
+		query = query.Where(sq.Eq{"e.team_id": filter.TeamID.UUID})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var points []domain.ActivityPoint
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "statisticRepo.GetActivity")
+
+	err = tr.SelectContext(ctx, &points, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// GetLifecycleStatistic aggregates creation to merge times and reassignments
+// of PRs. Every unassignment of a reviewer counts as a reassignment. Teams are
+// resolved like in GetTeamsStatistic; PRs without any team are left out of the
+// team grouping. The overall grouping always returns exactly one row.
+func (r *statisticRepo) GetLifecycleStatistic(
+	ctx context.Context,
+	filter domain.LifecycleFilter,
+) ([]domain.LifecycleStatistic, error) {
+	keyColumn := "''"
+
+	switch filter.Grouping {
+	case domain.LifecycleByTeam:
+		keyColumn = "t.name"
+	case domain.LifecycleByAuthor:
+		keyColumn = "pr.author_id"
+	}
+
+	mergeSeconds := "EXTRACT(EPOCH FROM pr.merged_at - pr.created_at)::float8"
+
+	query := r.qb.
+		Select(
+			keyColumn+" AS key",
+			"COUNT(*) AS prs_created",
+			"COUNT(pr.merged_at) AS prs_merged",
+			"AVG("+mergeSeconds+") AS avg_merge_seconds",
+			"percentile_cont(0.5) WITHIN GROUP (ORDER BY "+mergeSeconds+") AS p50_merge_seconds",
+			"percentile_cont(0.9) WITHIN GROUP (ORDER BY "+mergeSeconds+") AS p90_merge_seconds",
+			"percentile_cont(0.95) WITHIN GROUP (ORDER BY "+mergeSeconds+") AS p95_merge_seconds",
+			"COALESCE(SUM(ra.reassignments), 0) AS reassignments",
+			"COALESCE(MAX(ra.reassignments), 0) AS max_reassignments",
+			"COUNT(pr.required_reviewers) AS prs_with_staffing",
+			"COUNT(*) FILTER (WHERE pr.initial_reviewers < pr.required_reviewers) AS understaffed_prs",
+		).
+		From("pull_requests pr").
+		Join("users a ON a.id = pr.author_id").
+		LeftJoin("teams t ON t.id = COALESCE(pr.team_id, a.team_id)").
+		LeftJoin(
+			"(SELECT pull_request_id, COUNT(*) AS reassignments FROM review_events "+
+				"WHERE event_type = ? GROUP BY pull_request_id) ra ON ra.pull_request_id = pr.id",
+			dto.EventUnassigned,
+		)
+
+	if filter.Grouping != domain.LifecycleOverall {
+		query = query.GroupBy("key").OrderBy("key")
+	}
+
+	if filter.Grouping == domain.LifecycleByTeam {
+		query = query.Where(sq.NotEq{"t.id": nil})
+	}
+
+	if filter.From != nil {
+		query = query.Where(sq.GtOrEq{"pr.created_at": *filter.From})
+	}
+
+	if filter.To != nil {
+		query = query.Where(sq.Lt{"pr.created_at": *filter.To})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var statistics []domain.LifecycleStatistic
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "statisticRepo.GetLifecycleStatistic")
+
+	err = tr.SelectContext(ctx, &statistics, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return statistics, nil
+}
+
+// GetReviewLoads returns the review load of every active member of the
+// non-archived teams who can be assigned, including members with no reviews.
+// Only assignments made on PRs of the same team between from and to count.
+func (r *statisticRepo) GetReviewLoads(ctx context.Context, from *time.Time, to *time.Time) ([]domain.ReviewLoad, error) {
+	eventsJoin := sq.And{
+		sq.Expr("e.user_id = m.user_id"),
+		sq.Expr("e.team_id = m.team_id"),
+		sq.Eq{"e.event_type": dto.EventAssigned},
+	}
+
+	if from != nil {
+		eventsJoin = append(eventsJoin, sq.GtOrEq{"e.created_at": *from})
+	}
+
+	if to != nil {
+		eventsJoin = append(eventsJoin, sq.Lt{"e.created_at": *to})
+	}
+
+	joinSql, joinArgs, err := eventsJoin.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.qb.
+		Select("t.id AS team_id", "t.name AS team_name", "m.user_id", "COUNT(e.id) AS load").
+		Prefix(
+			"WITH members AS ("+
+				"SELECT id AS user_id, team_id, role FROM users WHERE team_id IS NOT NULL AND is_active "+
+				"UNION SELECT tm.user_id, tm.team_id, tm.role FROM team_memberships tm "+
+				"JOIN users u ON u.id = tm.user_id WHERE u.is_active)",
+		).
+		From("members m").
+		Join("teams t ON t.id = m.team_id").
+		LeftJoin("review_events e ON "+joinSql, joinArgs...).
+		Where(sq.Eq{"t.archived_at": nil}).
+		Where(sq.NotEq{"m.role": dto.TeamRoleObserver}).
+		GroupBy("t.id", "t.name", "m.user_id").
+		OrderBy("t.name", "m.user_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var loads []domain.ReviewLoad
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "statisticRepo.GetReviewLoads")
+
+	err = tr.SelectContext(ctx, &loads, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return loads, nil
+}
+
+// GetReviewGraph counts the reviews between every reviewer and author pair.
+// Only current reviewer assignments are taken into account, PRs are
+// attributed to teams like in GetTeamsStatistic.
+func (r *statisticRepo) GetReviewGraph(ctx context.Context, filter domain.ReviewGraphFilter) ([]domain.ReviewEdge, error) {
+	query := r.qb.
+		Select(
+			"prr.user_id AS reviewer_id",
+			"rv.username AS reviewer_name",
+			"pr.author_id",
+			"a.username AS author_name",
+			"COUNT(*) AS reviews",
+		).
+		From("pull_request_reviewers prr").
+		Join("pull_requests pr ON pr.id = prr.pull_request_id").
+		Join("users rv ON rv.id = prr.user_id").
+		Join("users a ON a.id = pr.author_id").
+		GroupBy("prr.user_id", "rv.username", "pr.author_id", "a.username").
+		OrderBy("prr.user_id", "pr.author_id")
+
+	if filter.TeamID.Valid {
+		query = query.Where("COALESCE(pr.team_id, a.team_id) = ?", filter.TeamID.UUID)
+	}
+
+	if filter.From != nil {
+		query = query.Where(sq.GtOrEq{"prr.assigned_at": *filter.From})
+	}
+
+	if filter.To != nil {
+		query = query.Where(sq.Lt{"prr.assigned_at": *filter.To})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var edges []domain.ReviewEdge
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "statisticRepo.GetReviewGraph")
+
+	err = tr.SelectContext(ctx, &edges, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return edges, nil
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type teamMembershipHistoryRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewTeamMembershipHistoryRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *teamMembershipHistoryRepo {
+	return &teamMembershipHistoryRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+func (r *teamMembershipHistoryRepo) SaveBatch(ctx context.Context, changes []domain.TeamMembershipChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	query := r.qb.
+		Insert("team_membership_history").
+		Columns("user_id", "from_team_id", "to_team_id", "changed_at")
+
+	for _, change := range changes {
+		query = query.Values(change.UserID, change.FromTeamID, change.ToTeamID, change.ChangedAt)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamMembershipHistoryRepo.SaveBatch")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *teamMembershipHistoryRepo) GetByUserID(ctx context.Context, userId string) ([]domain.TeamMembershipChange, error) {
+	query := r.qb.
+		Select("id", "user_id", "from_team_id", "to_team_id", "changed_at").
+		From("team_membership_history").
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("changed_at", "id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []domain.TeamMembershipChange
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamMembershipHistoryRepo.GetByUserID")
+
+	err = tr.SelectContext(ctx, &changes, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
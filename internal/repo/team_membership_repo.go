@@ -0,0 +1,137 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type teamMembershipRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewTeamMembershipRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *teamMembershipRepo {
+	return &teamMembershipRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+func (r *teamMembershipRepo) Save(ctx context.Context, membership domain.TeamMembership) (domain.TeamMembership, error) {
+	query := r.qb.
+		Insert("team_memberships").
+		Columns("user_id", "team_id", "role").
+		Values(membership.UserID, membership.TeamID, membership.Role).
+		Suffix("RETURNING user_id, team_id, role, joined_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.TeamMembership{}, err
+	}
+
+	var createdMembership domain.TeamMembership
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamMembershipRepo.Save")
+
+	err = tr.GetContext(ctx, &createdMembership, sql, args...)
+	if err != nil {
+		return domain.TeamMembership{}, err
+	}
+
+	return createdMembership, nil
+}
+
+func (r *teamMembershipRepo) GetByUserID(ctx context.Context, userId string) ([]domain.TeamMembership, error) {
+	query := r.qb.
+		Select("user_id", "team_id", "role", "joined_at").
+		From("team_memberships").
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("joined_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var memberships []domain.TeamMembership
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamMembershipRepo.GetByUserID")
+
+	err = tr.SelectContext(ctx, &memberships, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return memberships, nil
+}
+
+func (r *teamMembershipRepo) Update(ctx context.Context, membership domain.TeamMembership) (domain.TeamMembership, error) {
+	query := r.qb.
+		Update("team_memberships").
+		Set("role", membership.Role).
+		Where(sq.Eq{"user_id": membership.UserID, "team_id": membership.TeamID}).
+		Suffix("RETURNING user_id, team_id, role, joined_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.TeamMembership{}, err
+	}
+
+	var updatedMembership domain.TeamMembership
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamMembershipRepo.Update")
+
+	err = tr.GetContext(ctx, &updatedMembership, sql, args...)
+	if err != nil {
+		return domain.TeamMembership{}, err
+	}
+
+	return updatedMembership, nil
+}
+
+func (r *teamMembershipRepo) Delete(ctx context.Context, userId string, teamId uuid.UUID) error {
+	query := r.qb.
+		Delete("team_memberships").
+		Where(sq.Eq{"user_id": userId, "team_id": teamId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamMembershipRepo.Delete")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *teamMembershipRepo) DeleteByUserID(ctx context.Context, userId string) error {
+	query := r.qb.
+		Delete("team_memberships").
+		Where(sq.Eq{"user_id": userId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamMembershipRepo.DeleteByUserID")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
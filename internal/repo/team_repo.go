@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"strings"
 
 	"github.com/L11D/avito-review-assign-service/internal/domain"
 	sq "github.com/Masterminds/squirrel"
@@ -10,6 +11,8 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type teamRepo struct {
 	db     *sqlx.DB
 	qb     sq.StatementBuilderType
@@ -27,9 +30,9 @@ func NewTeamRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *teamRepo {
 func (r *teamRepo) Save(ctx context.Context, team domain.Team) (domain.Team, error) {
 	query := r.qb.
 		Insert("teams").
-		Columns("name").
-		Values(team.Name).
-		Suffix("RETURNING id, name")
+		Columns("name", "parent_id").
+		Values(team.Name, team.ParentID).
+		Suffix("RETURNING id, name, archived_at, parent_id")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -38,7 +41,9 @@ func (r *teamRepo) Save(ctx context.Context, team domain.Team) (domain.Team, err
 
 	var createdTeam domain.Team
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &createdTeam, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamRepo.Save")
+
+	err = tr.GetContext(ctx, &createdTeam, sql, args...)
 	if err != nil {
 		return domain.Team{}, err
 	}
@@ -48,7 +53,7 @@ func (r *teamRepo) Save(ctx context.Context, team domain.Team) (domain.Team, err
 
 func (r *teamRepo) GetByName(ctx context.Context, name string) (domain.Team, error) {
 	query := r.qb.
-		Select("id", "name").
+		Select("id", "name", "archived_at", "parent_id").
 		From("teams").
 		Where(sq.Eq{"name": name})
 
@@ -59,7 +64,9 @@ func (r *teamRepo) GetByName(ctx context.Context, name string) (domain.Team, err
 
 	var team domain.Team
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &team, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamRepo.GetByName")
+
+	err = tr.GetContext(ctx, &team, sql, args...)
 	if err != nil {
 		return domain.Team{}, err
 	}
@@ -69,7 +76,7 @@ func (r *teamRepo) GetByName(ctx context.Context, name string) (domain.Team, err
 
 func (r *teamRepo) GetByID(ctx context.Context, id uuid.UUID) (domain.Team, error) {
 	query := r.qb.
-		Select("id", "name").
+		Select("id", "name", "archived_at", "parent_id").
 		From("teams").
 		Where(sq.Eq{"id": id})
 
@@ -80,10 +87,161 @@ func (r *teamRepo) GetByID(ctx context.Context, id uuid.UUID) (domain.Team, erro
 
 	var team domain.Team
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &team, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamRepo.GetByID")
+
+	err = tr.GetContext(ctx, &team, sql, args...)
 	if err != nil {
 		return domain.Team{}, err
 	}
 
 	return team, nil
 }
+
+func (r *teamRepo) Update(ctx context.Context, team domain.Team) (domain.Team, error) {
+	query := r.qb.
+		Update("teams").
+		Set("name", team.Name).
+		Set("archived_at", team.ArchivedAt).
+		Set("parent_id", team.ParentID).
+		Where(sq.Eq{"id": team.ID}).
+		Suffix("RETURNING id, name, archived_at, parent_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	var updatedTeam domain.Team
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamRepo.Update")
+
+	err = tr.GetContext(ctx, &updatedTeam, sql, args...)
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	return updatedTeam, nil
+}
+
+// HasPullRequestReferences reports whether any member of the team authored,
+// reviewed or declined a PR.
+func (r *teamRepo) HasPullRequestReferences(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := r.qb.
+		Select("1").
+		From("users u").
+		Where(sq.Eq{"u.team_id": id}).
+		Where(sq.Or{
+			sq.Expr("EXISTS (SELECT 1 FROM pull_requests pr WHERE pr.author_id = u.id)"),
+			sq.Expr("EXISTS (SELECT 1 FROM pull_request_reviewers prr WHERE prr.user_id = u.id)"),
+			sq.Expr("EXISTS (SELECT 1 FROM pull_request_declines prd WHERE prd.user_id = u.id)"),
+			sq.Expr("EXISTS (SELECT 1 FROM review_sla_events e WHERE u.id IN (e.reviewer_id, e.new_reviewer_id))"),
+			sq.Expr("EXISTS (SELECT 1 FROM review_events re WHERE re.user_id = u.id)"),
+		}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamRepo.HasPullRequestReferences")
+
+	err = tr.GetContext(ctx, &exists, sql, args...)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *teamRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	query := r.qb.
+		Delete("teams").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamRepo.Delete")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List returns a page of teams ordered by name together with the number of
+// their active and inactive members.
+func (r *teamRepo) List(ctx context.Context, filter domain.TeamFilter) ([]domain.TeamSummary, error) {
+	query := r.applyTeamFilter(r.qb.
+		Select(
+			"t.id",
+			"t.name",
+			"t.archived_at",
+			"COUNT(u.id) AS members_count",
+			"COUNT(u.id) FILTER (WHERE u.is_active) AS active_members_count",
+		).
+		From("teams t").
+		LeftJoin("users u ON u.team_id = t.id"), filter).
+		GroupBy("t.id").
+		OrderBy("t.name").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var teams []domain.TeamSummary
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamRepo.List")
+
+	err = tr.SelectContext(ctx, &teams, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+func (r *teamRepo) Count(ctx context.Context, filter domain.TeamFilter) (int, error) {
+	query := r.applyTeamFilter(r.qb.
+		Select("COUNT(*)").
+		From("teams t"), filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamRepo.Count")
+
+	err = tr.GetContext(ctx, &count, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *teamRepo) applyTeamFilter(query sq.SelectBuilder, filter domain.TeamFilter) sq.SelectBuilder {
+	if filter.NamePrefix != "" {
+		query = query.Where(sq.Like{"t.name": likeEscaper.Replace(filter.NamePrefix) + "%"})
+	}
+
+	if !filter.IncludeArchived {
+		query = query.Where(sq.Eq{"t.archived_at": nil})
+	}
+
+	return query
+}
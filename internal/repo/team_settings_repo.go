@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type teamSettingsRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewTeamSettingsRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *teamSettingsRepo {
+	return &teamSettingsRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+// Upsert applies the patch in a single statement, so concurrent updates of
+// different fields do not overwrite each other. A team without stored
+// settings gets the defaults for the fields missing from the patch.
+func (r *teamSettingsRepo) Upsert(
+	ctx context.Context,
+	defaults domain.TeamSettings,
+	patch domain.TeamSettingsPatch,
+) (domain.TeamSettings, error) {
+	settings := defaults
+	updates := []string{}
+
+	if patch.ReviewersCount != nil {
+		settings.ReviewersCount = *patch.ReviewersCount
+		updates = append(updates, "reviewers_count = EXCLUDED.reviewers_count")
+	}
+
+	if patch.Strategy != nil {
+		settings.Strategy = *patch.Strategy
+		updates = append(updates, "strategy = EXCLUDED.strategy")
+	}
+
+	if patch.OwnTeamRule != nil {
+		settings.OwnTeamRule = *patch.OwnTeamRule
+		updates = append(updates, "own_team_rule = EXCLUDED.own_team_rule")
+	}
+
+	updates = append(updates, "updated_at = NOW()")
+
+	query := r.qb.
+		Insert("team_settings").
+		Columns("team_id", "reviewers_count", "strategy", "own_team_rule").
+		Values(patch.TeamID, settings.ReviewersCount, settings.Strategy, settings.OwnTeamRule).
+		Suffix("ON CONFLICT (team_id) DO UPDATE SET " +
+			strings.Join(updates, ", ") +
+			" RETURNING team_id, reviewers_count, strategy, own_team_rule, updated_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	var savedSettings domain.TeamSettings
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamSettingsRepo.Upsert")
+
+	err = tr.GetContext(ctx, &savedSettings, sql, args...)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	return savedSettings, nil
+}
+
+func (r *teamSettingsRepo) GetByTeamID(ctx context.Context, teamId uuid.UUID) (domain.TeamSettings, error) {
+	query := r.qb.
+		Select("team_id", "reviewers_count", "strategy", "own_team_rule", "updated_at").
+		From("team_settings").
+		Where(sq.Eq{"team_id": teamId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	var settings domain.TeamSettings
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamSettingsRepo.GetByTeamID")
+
+	err = tr.GetContext(ctx, &settings, sql, args...)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	return settings, nil
+}
+
+func (r *teamSettingsRepo) GetByTeamIDs(ctx context.Context, teamIds []uuid.UUID) ([]domain.TeamSettings, error) {
+	query := r.qb.
+		Select("team_id", "reviewers_count", "strategy", "own_team_rule", "updated_at").
+		From("team_settings").
+		Where("team_id = ANY(?)", pq.Array(teamIds))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []domain.TeamSettings
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamSettingsRepo.GetByTeamIDs")
+
+	err = tr.SelectContext(ctx, &settings, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type teamSLAPolicyRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewTeamSLAPolicyRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *teamSLAPolicyRepo {
+	return &teamSLAPolicyRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+func (r *teamSLAPolicyRepo) Upsert(ctx context.Context, policy domain.TeamSLAPolicy) (domain.TeamSLAPolicy, error) {
+	query := r.qb.
+		Insert("team_sla_policies").
+		Columns("team_id", "review_sla_seconds", "action", "backup_reviewer_id").
+		Values(policy.TeamID, policy.ReviewSLASeconds, policy.Action, policy.BackupReviewerID).
+		Suffix("ON CONFLICT (team_id) DO UPDATE SET " +
+			"review_sla_seconds = EXCLUDED.review_sla_seconds, " +
+			"action = EXCLUDED.action, " +
+			"backup_reviewer_id = EXCLUDED.backup_reviewer_id " +
+			"RETURNING team_id, review_sla_seconds, action, backup_reviewer_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.TeamSLAPolicy{}, err
+	}
+
+	var savedPolicy domain.TeamSLAPolicy
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamSLAPolicyRepo.Upsert")
+
+	err = tr.GetContext(ctx, &savedPolicy, sql, args...)
+	if err != nil {
+		return domain.TeamSLAPolicy{}, err
+	}
+
+	return savedPolicy, nil
+}
+
+func (r *teamSLAPolicyRepo) GetByTeamID(ctx context.Context, teamId uuid.UUID) (domain.TeamSLAPolicy, error) {
+	query := r.qb.
+		Select("team_id", "review_sla_seconds", "action", "backup_reviewer_id").
+		From("team_sla_policies").
+		Where(sq.Eq{"team_id": teamId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.TeamSLAPolicy{}, err
+	}
+
+	var policy domain.TeamSLAPolicy
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamSLAPolicyRepo.GetByTeamID")
+
+	err = tr.GetContext(ctx, &policy, sql, args...)
+	if err != nil {
+		return domain.TeamSLAPolicy{}, err
+	}
+
+	return policy, nil
+}
+
+// ResetBackupsFromTeam switches policies whose backup reviewer is a member of
+// the team back to reassignment, so they do not escalate to nobody once the
+// team members are deleted.
+func (r *teamSLAPolicyRepo) ResetBackupsFromTeam(ctx context.Context, teamId uuid.UUID) error {
+	query := r.qb.
+		Update("team_sla_policies").
+		Set("action", dto.SLAActionReassign).
+		Set("backup_reviewer_id", nil).
+		Where("backup_reviewer_id IN (SELECT id FROM users WHERE team_id = ?)", teamId)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "teamSLAPolicyRepo.ResetBackupsFromTeam")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
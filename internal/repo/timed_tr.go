@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/internal/metrics"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// timedTr reports the duration of every query to metrics.DBQueryDuration,
+// labelled with the repository method that issued it.
+type timedTr struct {
+	trmsqlx.Tr
+	query string
+}
+
+// eachRow runs the query and passes the rows to fn one by one without loading
+// them all into memory. Iteration stops at the first error returned by fn.
+func eachRow[T any](ctx context.Context, tr timedTr, query string, args []interface{}, fn func(T) error) error {
+	rows, err := tr.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var row T
+
+		err = rows.StructScan(&row)
+		if err != nil {
+			return err
+		}
+
+		err = fn(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// timed wraps the transaction or database of a repository method. The label
+// names the method, e.g. "userRepo.GetByID".
+func timed(tr trmsqlx.Tr, query string) timedTr {
+	return timedTr{Tr: tr, query: query}
+}
+
+func (t timedTr) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	defer t.observe(time.Now())
+
+	return t.Tr.GetContext(ctx, dest, query, args...)
+}
+
+func (t timedTr) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	defer t.observe(time.Now())
+
+	return t.Tr.SelectContext(ctx, dest, query, args...)
+}
+
+func (t timedTr) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer t.observe(time.Now())
+
+	return t.Tr.ExecContext(ctx, query, args...)
+}
+
+// QueryxContext only measures the query until its first rows are ready, the
+// time spent iterating over them depends on the caller.
+func (t timedTr) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	defer t.observe(time.Now())
+
+	return t.Tr.QueryxContext(ctx, query, args...)
+}
+
+func (t timedTr) observe(start time.Time) {
+	metrics.DBQueryDuration.WithLabelValues(t.query).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	sq "github.com/Masterminds/squirrel"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type userIdentityRepo struct {
+	db     *sqlx.DB
+	qb     sq.StatementBuilderType
+	getter *trmsqlx.CtxGetter
+}
+
+func NewUserIdentityRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *userIdentityRepo {
+	return &userIdentityRepo{
+		db:     db,
+		qb:     sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		getter: getter,
+	}
+}
+
+func (r *userIdentityRepo) Save(ctx context.Context, identity domain.UserIdentity) (domain.UserIdentity, error) {
+	query := r.qb.
+		Insert("user_identities").
+		Columns("provider", "external_id", "user_id").
+		Values(identity.Provider, identity.ExternalID, identity.UserID).
+		Suffix("RETURNING provider, external_id, user_id, created_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.UserIdentity{}, err
+	}
+
+	var createdIdentity domain.UserIdentity
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userIdentityRepo.Save")
+
+	err = tr.GetContext(ctx, &createdIdentity, sql, args...)
+	if err != nil {
+		return domain.UserIdentity{}, err
+	}
+
+	return createdIdentity, nil
+}
+
+func (r *userIdentityRepo) GetByExternalID(
+	ctx context.Context,
+	provider dto.IdentityProvider,
+	externalId string,
+) (domain.UserIdentity, error) {
+	query := r.qb.
+		Select("provider", "external_id", "user_id", "created_at").
+		From("user_identities").
+		Where(sq.Eq{"provider": provider, "external_id": externalId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.UserIdentity{}, err
+	}
+
+	var identity domain.UserIdentity
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userIdentityRepo.GetByExternalID")
+
+	err = tr.GetContext(ctx, &identity, sql, args...)
+	if err != nil {
+		return domain.UserIdentity{}, err
+	}
+
+	return identity, nil
+}
+
+func (r *userIdentityRepo) GetByUserID(ctx context.Context, userId string) ([]domain.UserIdentity, error) {
+	query := r.qb.
+		Select("provider", "external_id", "user_id", "created_at").
+		From("user_identities").
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("provider", "external_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var identities []domain.UserIdentity
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userIdentityRepo.GetByUserID")
+
+	err = tr.SelectContext(ctx, &identities, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// Delete removes the identity only while it belongs to the given user and
+// returns sql.ErrNoRows otherwise.
+func (r *userIdentityRepo) Delete(ctx context.Context, identity domain.UserIdentity) error {
+	query := r.qb.
+		Delete("user_identities").
+		Where(sq.Eq{
+			"provider":    identity.Provider,
+			"external_id": identity.ExternalID,
+			"user_id":     identity.UserID,
+		}).
+		Suffix("RETURNING provider")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	var provider dto.IdentityProvider
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userIdentityRepo.Delete")
+
+	return tr.GetContext(ctx, &provider, sql, args...)
+}
+
+func (r *userIdentityRepo) DeleteByUserID(ctx context.Context, userId string) error {
+	query := r.qb.
+		Delete("user_identities").
+		Where(sq.Eq{"user_id": userId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userIdentityRepo.DeleteByUserID")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,14 +2,23 @@ package repo
 
 import (
 	"context"
+	"strings"
 
 	"github.com/L11D/avito-review-assign-service/internal/domain"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	sq "github.com/Masterminds/squirrel"
 	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+var userColumns = []string{
+	"id", "username", "is_active", "team_id", "assign_rate", "role", "email", "full_name", "anonymized_at",
+}
+
+var userReturning = strings.Join(userColumns, ", ")
+
 type userRepo struct {
 	db     *sqlx.DB
 	qb     sq.StatementBuilderType
@@ -27,9 +36,9 @@ func NewUserRepo(db *sqlx.DB, getter *trmsqlx.CtxGetter) *userRepo {
 func (r *userRepo) Save(ctx context.Context, user domain.User) (domain.User, error) {
 	query := r.qb.
 		Insert("users").
-		Columns("id", "username", "is_active", "team_id").
-		Values(user.ID, user.Username, user.IsActive, user.TeamID).
-		Suffix("RETURNING id, username, team_id, is_active, assign_rate")
+		Columns("id", "username", "is_active", "team_id", "role").
+		Values(user.ID, user.Username, user.IsActive, user.TeamID, user.Role).
+		Suffix("RETURNING " + userReturning)
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -38,7 +47,9 @@ func (r *userRepo) Save(ctx context.Context, user domain.User) (domain.User, err
 
 	var createdUser domain.User
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &createdUser, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.Save")
+
+	err = tr.GetContext(ctx, &createdUser, sql, args...)
 	if err != nil {
 		return domain.User{}, err
 	}
@@ -48,7 +59,7 @@ func (r *userRepo) Save(ctx context.Context, user domain.User) (domain.User, err
 
 func (r *userRepo) GetByTeamID(ctx context.Context, teamId uuid.UUID) ([]domain.User, error) {
 	query := r.qb.
-		Select("id", "username", "is_active", "team_id", "assign_rate").
+		Select(userColumns...).
 		From("users").
 		Where(sq.Eq{"team_id": teamId})
 
@@ -59,7 +70,9 @@ func (r *userRepo) GetByTeamID(ctx context.Context, teamId uuid.UUID) ([]domain.
 
 	var users []domain.User
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).SelectContext(ctx, &users, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.GetByTeamID")
+
+	err = tr.SelectContext(ctx, &users, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -74,8 +87,9 @@ func (r *userRepo) Update(ctx context.Context, user domain.User) (domain.User, e
 		Set("is_active", user.IsActive).
 		Set("team_id", user.TeamID).
 		Set("assign_rate", user.AssignRate).
+		Set("role", user.Role).
 		Where(sq.Eq{"id": user.ID}).
-		Suffix("RETURNING id, username, is_active, team_id, assign_rate")
+		Suffix("RETURNING " + userReturning)
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -84,7 +98,9 @@ func (r *userRepo) Update(ctx context.Context, user domain.User) (domain.User, e
 
 	var updatedUser domain.User
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &updatedUser, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.Update")
+
+	err = tr.GetContext(ctx, &updatedUser, sql, args...)
 	if err != nil {
 		return domain.User{}, err
 	}
@@ -92,9 +108,69 @@ func (r *userRepo) Update(ctx context.Context, user domain.User) (domain.User, e
 	return updatedUser, nil
 }
 
+// UpdateProfile changes only the personal fields, so it never races with
+// roster or assign_rate updates.
+func (r *userRepo) UpdateProfile(ctx context.Context, user domain.User) (domain.User, error) {
+	query := r.qb.
+		Update("users").
+		Set("username", user.Username).
+		Set("email", user.Email).
+		Set("full_name", user.FullName).
+		Where(sq.Eq{"id": user.ID}).
+		Suffix("RETURNING " + userReturning)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	var updatedUser domain.User
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.UpdateProfile")
+
+	err = tr.GetContext(ctx, &updatedUser, sql, args...)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return updatedUser, nil
+}
+
+// Anonymize replaces the personal data with username, deactivates the user and
+// detaches them from their team. The row itself stays for the PR history.
+func (r *userRepo) Anonymize(ctx context.Context, userId string, username string) (domain.User, error) {
+	query := r.qb.
+		Update("users").
+		Set("username", username).
+		Set("email", nil).
+		Set("full_name", nil).
+		Set("is_active", false).
+		Set("team_id", nil).
+		Set("role", dto.TeamRoleMember).
+		Set("anonymized_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": userId}).
+		Suffix("RETURNING " + userReturning)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	var anonymizedUser domain.User
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.Anonymize")
+
+	err = tr.GetContext(ctx, &anonymizedUser, sql, args...)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return anonymizedUser, nil
+}
+
 func (r *userRepo) GetByID(ctx context.Context, userId string) (domain.User, error) {
 	query := r.qb.
-		Select("id", "username", "is_active", "team_id", "assign_rate").
+		Select(userColumns...).
 		From("users").
 		Where(sq.Eq{"id": userId})
 
@@ -105,7 +181,9 @@ func (r *userRepo) GetByID(ctx context.Context, userId string) (domain.User, err
 
 	var user domain.User
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).GetContext(ctx, &user, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.GetByID")
+
+	err = tr.GetContext(ctx, &user, sql, args...)
 	if err != nil {
 		return domain.User{}, err
 	}
@@ -113,10 +191,11 @@ func (r *userRepo) GetByID(ctx context.Context, userId string) (domain.User, err
 	return user, nil
 }
 
-func (r *userRepo) GetAll(ctx context.Context) ([]domain.User, error) {
+func (r *userRepo) GetByIDs(ctx context.Context, userIds []string) ([]domain.User, error) {
 	query := r.qb.
-		Select("id", "username", "is_active", "team_id", "assign_rate").
-		From("users")
+		Select(userColumns...).
+		From("users").
+		Where("id = ANY(?)", pq.Array(userIds))
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -125,10 +204,114 @@ func (r *userRepo) GetAll(ctx context.Context) ([]domain.User, error) {
 
 	var users []domain.User
 
-	err = r.getter.DefaultTrOrDB(ctx, r.db).SelectContext(ctx, &users, sql, args...)
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.GetByIDs")
+
+	err = tr.SelectContext(ctx, &users, sql, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	return users, nil
 }
+
+// GetMembersByTeamIDs returns everyone who can review for the given teams:
+// users whose primary team it is and users with an additional membership.
+func (r *userRepo) GetMembersByTeamIDs(ctx context.Context, teamIds []uuid.UUID) ([]domain.TeamMember, error) {
+	query := r.qb.
+		Select("u.id", "u.username", "u.is_active", "u.team_id", "u.assign_rate", "m.role", "m.team_id AS pool_team_id").
+		From("users u").
+		Join(
+			"(SELECT id AS user_id, team_id, role FROM users WHERE team_id = ANY(?) "+
+				"UNION SELECT user_id, team_id, role FROM team_memberships WHERE team_id = ANY(?)) m "+
+				"ON m.user_id = u.id",
+			pq.Array(teamIds),
+			pq.Array(teamIds),
+		)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var members []domain.TeamMember
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.GetMembersByTeamIDs")
+
+	err = tr.SelectContext(ctx, &members, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *userRepo) SetIsActiveByIDs(ctx context.Context, userIds []string, isActive bool) ([]domain.User, error) {
+	query := r.qb.
+		Update("users").
+		Set("is_active", isActive).
+		Where("id = ANY(?)", pq.Array(userIds)).
+		Suffix("RETURNING " + userReturning)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedUsers []domain.User
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.SetIsActiveByIDs")
+
+	err = tr.SelectContext(ctx, &updatedUsers, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedUsers, nil
+}
+
+// SetTeamByIDs moves the users to another team, or out of any team. The role
+// belongs to the old membership, so it is reset to member.
+func (r *userRepo) SetTeamByIDs(ctx context.Context, userIds []string, teamId uuid.NullUUID) ([]domain.User, error) {
+	query := r.qb.
+		Update("users").
+		Set("team_id", teamId).
+		Set("role", dto.TeamRoleMember).
+		Where("id = ANY(?)", pq.Array(userIds)).
+		Suffix("RETURNING " + userReturning)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedUsers []domain.User
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.SetTeamByIDs")
+
+	err = tr.SelectContext(ctx, &updatedUsers, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedUsers, nil
+}
+
+func (r *userRepo) DeleteByTeamID(ctx context.Context, teamId uuid.UUID) error {
+	query := r.qb.
+		Delete("users").
+		Where(sq.Eq{"team_id": teamId})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tr := timed(r.getter.DefaultTrOrDB(ctx, r.db), "userRepo.DeleteByTeamID")
+
+	_, err = tr.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,60 +4,106 @@ import (
 	"context"
 	"errors"
 	"slices"
-	"sort"
 	"time"
 
 	"github.com/L11D/avito-review-assign-service/internal/domain"
 	appErrors "github.com/L11D/avito-review-assign-service/internal/errors"
+	"github.com/L11D/avito-review-assign-service/internal/metrics"
 	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
 	"github.com/google/uuid"
 )
 
-const MAX_REVIEWERS_PER_PR = 2
-
 type PullRequestRepo interface {
 	Save(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error)
 	Update(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error)
 	GetByID(ctx context.Context, prId string) (domain.PullRequest, error)
+	GetByIDForUpdate(ctx context.Context, prId string) (domain.PullRequest, error)
+	SetStaffing(ctx context.Context, prId string, required int, initial int) error
 }
 
 type PullRequestReviewerRepo interface {
 	Save(ctx context.Context, prReviewer domain.PullRequestReviewer) (domain.PullRequestReviewer, error)
 	GetPRUsersIds(ctx context.Context, prId string) ([]string, error)
 	DeleteByPRAndUserId(ctx context.Context, prId string, userId string) error
+	SetVerdict(ctx context.Context, prId string, userId string, verdict dto.ReviewVerdict) error
+}
+
+type PullRequestDeclineRepo interface {
+	Save(ctx context.Context, decline domain.PullRequestDecline) (domain.PullRequestDecline, error)
+	GetPRUsersIds(ctx context.Context, prId string) ([]string, error)
+}
+
+type ReviewEventRepoPRService interface {
+	SaveBatch(ctx context.Context, events []domain.ReviewEvent) error
 }
 
 type UserRepoPRService interface {
 	GetByID(ctx context.Context, userId string) (domain.User, error)
-	GetByTeamID(ctx context.Context, teamId uuid.UUID) ([]domain.User, error)
+}
+
+type TeamRepoPRService interface {
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Team, error)
+	GetByName(ctx context.Context, name string) (domain.Team, error)
+}
+
+type TeamMembershipRepoPRService interface {
+	GetByUserID(ctx context.Context, userId string) ([]domain.TeamMembership, error)
 }
 
 type UserServicePRService interface {
 	IncrementAssignRate(ctx context.Context, userId string) (domain.User, error)
+	GetTeamRole(ctx context.Context, userId string, teamId uuid.UUID) (dto.TeamRole, error)
+}
+
+type ReviewerServicePRService interface {
+	SelectReviewers(
+		ctx context.Context,
+		authorId string,
+		teamId uuid.NullUUID,
+		excludeIds []string,
+	) ([]string, error)
+	ReplaceReviewer(ctx context.Context, prId string, oldReviewerId string, newReviewerId string) error
+	GetReviewersCount(ctx context.Context, teamId uuid.NullUUID) (int, error)
+	IsEligibleReviewer(ctx context.Context, authorId string, teamId uuid.NullUUID, userId string) (bool, error)
 }
 
 type pullRequestService struct {
-	PRRepo         PullRequestRepo
-	PRReviewerRepo PullRequestReviewerRepo
-	userRepo       UserRepoPRService
-	userService    UserServicePRService
-	trManager      *manager.Manager
+	PRRepo          PullRequestRepo
+	PRReviewerRepo  PullRequestReviewerRepo
+	PRDeclineRepo   PullRequestDeclineRepo
+	eventRepo       ReviewEventRepoPRService
+	userRepo        UserRepoPRService
+	teamRepo        TeamRepoPRService
+	membershipRepo  TeamMembershipRepoPRService
+	userService     UserServicePRService
+	reviewerService ReviewerServicePRService
+	trManager       *manager.Manager
 }
 
 func NewPullRequestService(
 	prRepo PullRequestRepo,
 	prReviewerRepo PullRequestReviewerRepo,
+	prDeclineRepo PullRequestDeclineRepo,
+	eventRepo ReviewEventRepoPRService,
 	userRepo UserRepoPRService,
+	teamRepo TeamRepoPRService,
+	membershipRepo TeamMembershipRepoPRService,
 	userService UserServicePRService,
+	reviewerService ReviewerServicePRService,
 	trManager *manager.Manager,
 ) *pullRequestService {
 	return &pullRequestService{
-		PRRepo:         prRepo,
-		PRReviewerRepo: prReviewerRepo,
-		userRepo:       userRepo,
-		userService:    userService,
-		trManager:      trManager,
+		PRRepo:          prRepo,
+		PRReviewerRepo:  prReviewerRepo,
+		PRDeclineRepo:   prDeclineRepo,
+		eventRepo:       eventRepo,
+		userRepo:        userRepo,
+		teamRepo:        teamRepo,
+		membershipRepo:  membershipRepo,
+		userService:     userService,
+		reviewerService: reviewerService,
+		trManager:       trManager,
 	}
 }
 
@@ -66,6 +112,11 @@ func (s *pullRequestService) Create(ctx context.Context, pr dto.PullRequestCreat
 		ID:       pr.ID,
 		Name:     pr.Name,
 		AuthorID: pr.AuthorID,
+		Priority: pr.Priority,
+	}
+
+	if domainPR.Priority == "" {
+		domainPR.Priority = dto.PriorityNormal
 	}
 
 	var (
@@ -73,7 +124,7 @@ func (s *pullRequestService) Create(ctx context.Context, pr dto.PullRequestCreat
 		createReviewerIds []string
 	)
 
-	_, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
 	if err != nil {
 		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
 			return dto.PullRequestDTO{}, appErrors.NewNotFoundError("User with ID '" + pr.AuthorID + "'")
@@ -82,6 +133,24 @@ func (s *pullRequestService) Create(ctx context.Context, pr dto.PullRequestCreat
 		return dto.PullRequestDTO{}, err
 	}
 
+	if pr.TeamName != nil {
+		team, err := s.getAuthorTeam(ctx, author, *pr.TeamName)
+		if err != nil {
+			return dto.PullRequestDTO{}, err
+		}
+
+		domainPR.TeamID = uuid.NullUUID{UUID: team.ID, Valid: true}
+	} else if author.TeamID.Valid {
+		team, err := s.teamRepo.GetByID(ctx, author.TeamID.UUID)
+		if err != nil {
+			return dto.PullRequestDTO{}, err
+		}
+
+		if team.ArchivedAt != nil {
+			return dto.PullRequestDTO{}, appErrors.NewTeamArchivedError(team.Name)
+		}
+	}
+
 	err = s.trManager.Do(ctx, func(ctx context.Context) error {
 		PR, err := s.PRRepo.Save(ctx, domainPR)
 		if err != nil {
@@ -92,11 +161,28 @@ func (s *pullRequestService) Create(ctx context.Context, pr dto.PullRequestCreat
 			return err
 		}
 
-		reviewersIds, err := s.getReviewsForUserPR(ctx, pr.AuthorID, []string{})
+		reviewersIds, err := s.reviewerService.SelectReviewers(ctx, pr.AuthorID, PR.TeamID, []string{})
+		if err != nil {
+			return err
+		}
+
+		staffingTeamId := PR.TeamID
+		if !staffingTeamId.Valid {
+			staffingTeamId = author.TeamID
+		}
+
+		requiredCount, err := s.reviewerService.GetReviewersCount(ctx, staffingTeamId)
+		if err != nil {
+			return err
+		}
+
+		err = s.PRRepo.SetStaffing(ctx, PR.ID, requiredCount, len(reviewersIds))
 		if err != nil {
 			return err
 		}
 
+		events := make([]domain.ReviewEvent, 0, len(reviewersIds))
+
 		for _, reviewerId := range reviewersIds {
 			prReviewer := domain.PullRequestReviewer{
 				PullRequestID: pr.ID,
@@ -109,16 +195,24 @@ func (s *pullRequestService) Create(ctx context.Context, pr dto.PullRequestCreat
 			}
 
 			createReviewerIds = append(createReviewerIds, createdPRReviewer.UserID)
+			events = append(events, domain.NewReviewEvent(dto.EventAssigned, pr.ID, reviewerId))
 		}
 
 		createdPR = PR
 
-		return nil
+		return s.eventRepo.SaveBatch(ctx, events)
 	})
 	if err != nil {
 		return dto.PullRequestDTO{}, err
 	}
 
+	metrics.PullRequestsCreated.Inc()
+	metrics.ReviewersPerPullRequest.Observe(float64(len(createReviewerIds)))
+
+	if len(createReviewerIds) == 0 {
+		metrics.NoCandidate.WithLabelValues(metrics.NoCandidateCreate).Inc()
+	}
+
 	return prToDTO(createdPR, createReviewerIds), nil
 }
 
@@ -182,16 +276,28 @@ func (s *pullRequestService) Reassign(
 		return dto.PullRequestDTO{}, err
 	}
 
-	newReviewersIds, err := s.getReviewsForUserPR(ctx, pr.AuthorID, returnedReviewerIds)
+	declinedIds, err := s.PRDeclineRepo.GetPRUsersIds(ctx, pr.ID)
+	if err != nil {
+		return dto.PullRequestDTO{}, err
+	}
+
+	newReviewersIds, err := s.reviewerService.SelectReviewers(
+		ctx,
+		pr.AuthorID,
+		pr.TeamID,
+		slices.Concat(returnedReviewerIds, declinedIds),
+	)
 	if err != nil {
 		return dto.PullRequestDTO{}, err
 	}
 
 	if len(newReviewersIds) < 1 {
+		metrics.NoCandidate.WithLabelValues(metrics.NoCandidateReassign).Inc()
+
 		return dto.PullRequestDTO{}, appErrors.NewNoCandidateError()
 	}
 
-	err = s.doReassign(ctx, pr.ID, newReviewersIds[0], reassignDTO.OldReviewerID)
+	err = s.reviewerService.ReplaceReviewer(ctx, pr.ID, reassignDTO.OldReviewerID, newReviewersIds[0])
 	if err != nil {
 		return dto.PullRequestDTO{}, err
 	}
@@ -204,32 +310,281 @@ func (s *pullRequestService) Reassign(
 	return prToDTO(pr, reviewerIds), nil
 }
 
-func (s *pullRequestService) doReassign(
+// OverrideReviewer lets a lead of the PR team replace a reviewer with a
+// person of their choice instead of the automatically selected one. The new
+// reviewer still has to be eligible for the PR team. The PR row stays locked
+// while the reviewers are checked and replaced.
+func (s *pullRequestService) OverrideReviewer(
+	ctx context.Context,
+	overrideDTO dto.PullRequestOverrideReviewerDTO,
+) (dto.PullRequestDTO, error) {
+	var (
+		pr          domain.PullRequest
+		reviewerIds []string
+	)
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+
+		pr, err = s.PRRepo.GetByIDForUpdate(ctx, overrideDTO.PullRequestID)
+		if err != nil {
+			if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+				return appErrors.NewNotFoundError("Pull Request with ID '" + overrideDTO.PullRequestID + "'")
+			}
+
+			return err
+		}
+
+		if pr.Status == dto.StatusMerged {
+			return appErrors.NewPullRequestMergedError()
+		}
+
+		err = s.checkPRLead(ctx, pr, overrideDTO.LeadID)
+		if err != nil {
+			return err
+		}
+
+		reviewerIds, err = s.PRReviewerRepo.GetPRUsersIds(ctx, pr.ID)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(reviewerIds, overrideDTO.OldReviewerID) {
+			return appErrors.NewNotAssignedError()
+		}
+
+		err = s.checkOverrideReviewer(ctx, pr, reviewerIds, overrideDTO.NewReviewerID)
+		if err != nil {
+			return err
+		}
+
+		err = s.reviewerService.ReplaceReviewer(ctx, pr.ID, overrideDTO.OldReviewerID, overrideDTO.NewReviewerID)
+		if err != nil {
+			return err
+		}
+
+		reviewerIds, err = s.PRReviewerRepo.GetPRUsersIds(ctx, pr.ID)
+
+		return err
+	})
+	if err != nil {
+		return dto.PullRequestDTO{}, err
+	}
+
+	return prToDTO(pr, reviewerIds), nil
+}
+
+// checkOverrideReviewer applies the rules of the automatic selection to a
+// reviewer picked by a lead.
+func (s *pullRequestService) checkOverrideReviewer(
 	ctx context.Context,
-	prId string,
+	pr domain.PullRequest,
+	reviewerIds []string,
 	newReviewerId string,
-	oldReviewerId string,
 ) error {
-	err := s.trManager.Do(ctx, func(ctx context.Context) error {
-		err := s.PRReviewerRepo.DeleteByPRAndUserId(ctx, prId, oldReviewerId)
+	newReviewer, err := s.userRepo.GetByID(ctx, newReviewerId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return appErrors.NewNotFoundError("User with ID '" + newReviewerId + "'")
+		}
+
+		return err
+	}
+
+	switch {
+	case !newReviewer.IsActive:
+		return appErrors.NewValidationFailedError("User '" + newReviewer.ID + "' is inactive")
+	case newReviewer.ID == pr.AuthorID:
+		return appErrors.NewValidationFailedError("The author cannot review their own pull request")
+	case slices.Contains(reviewerIds, newReviewer.ID):
+		return appErrors.NewValidationFailedError(
+			"User '" + newReviewer.ID + "' is already assigned to the pull request",
+		)
+	}
+
+	eligible, err := s.reviewerService.IsEligibleReviewer(ctx, pr.AuthorID, pr.TeamID, newReviewer.ID)
+	if err != nil {
+		return err
+	}
+
+	if !eligible {
+		return appErrors.NewValidationFailedError(
+			"User '" + newReviewer.ID + "' is not an eligible reviewer for the pull request team",
+		)
+	}
+
+	return nil
+}
+
+func (s *pullRequestService) SetPriority(
+	ctx context.Context,
+	priorityDTO dto.PullRequestSetPriorityDTO,
+) (dto.PullRequestDTO, error) {
+	pr, err := s.PRRepo.GetByID(ctx, priorityDTO.PullRequestID)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.PullRequestDTO{}, appErrors.NewNotFoundError("Pull Request with ID '" + priorityDTO.PullRequestID + "'")
+		}
+
+		return dto.PullRequestDTO{}, err
+	}
+
+	if pr.Priority != priorityDTO.Priority {
+		pr.Priority = priorityDTO.Priority
+
+		pr, err = s.PRRepo.Update(ctx, pr)
 		if err != nil {
+			return dto.PullRequestDTO{}, err
+		}
+	}
+
+	reviewerIds, err := s.PRReviewerRepo.GetPRUsersIds(ctx, pr.ID)
+	if err != nil {
+		return dto.PullRequestDTO{}, err
+	}
+
+	return prToDTO(pr, reviewerIds), nil
+}
+
+// SubmitVerdict stores the review outcome of an assigned reviewer. A later
+// verdict replaces the earlier one.
+func (s *pullRequestService) SubmitVerdict(
+	ctx context.Context,
+	verdictDTO dto.PullRequestVerdictDTO,
+) (dto.PullRequestDTO, error) {
+	pr, err := s.PRRepo.GetByID(ctx, verdictDTO.PullRequestID)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.PullRequestDTO{}, appErrors.NewNotFoundError("Pull Request with ID '" + verdictDTO.PullRequestID + "'")
+		}
+
+		return dto.PullRequestDTO{}, err
+	}
+
+	if pr.Status == dto.StatusMerged {
+		return dto.PullRequestDTO{}, appErrors.NewPullRequestMergedError()
+	}
+
+	reviewerIds, err := s.PRReviewerRepo.GetPRUsersIds(ctx, pr.ID)
+	if err != nil {
+		return dto.PullRequestDTO{}, err
+	}
+
+	if !slices.Contains(reviewerIds, verdictDTO.ReviewerID) {
+		return dto.PullRequestDTO{}, appErrors.NewNotAssignedError()
+	}
+
+	err = s.PRReviewerRepo.SetVerdict(ctx, pr.ID, verdictDTO.ReviewerID, verdictDTO.Verdict)
+	if err != nil {
+		return dto.PullRequestDTO{}, err
+	}
+
+	return prToDTO(pr, reviewerIds), nil
+}
+
+// Decline removes the reviewer from the PR on their own request, stores the
+// reason and assigns a replacement that has not declined this PR before.
+// When nobody is left the PR simply keeps one reviewer less.
+func (s *pullRequestService) Decline(
+	ctx context.Context,
+	declineDTO dto.PullRequestDeclineDTO,
+) (dto.PullRequestDeclineResultDTO, error) {
+	pr, err := s.PRRepo.GetByID(ctx, declineDTO.PullRequestID)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.PullRequestDeclineResultDTO{},
+				appErrors.NewNotFoundError("Pull Request with ID '" + declineDTO.PullRequestID + "'")
+		}
+
+		return dto.PullRequestDeclineResultDTO{}, err
+	}
+
+	if pr.Status == dto.StatusMerged {
+		return dto.PullRequestDeclineResultDTO{}, appErrors.NewPullRequestMergedError()
+	}
+
+	hasReviewer, err := s.prHasReviewer(ctx, pr.ID, declineDTO.ReviewerID)
+	if err != nil {
+		return dto.PullRequestDeclineResultDTO{}, err
+	}
+
+	if !hasReviewer {
+		return dto.PullRequestDeclineResultDTO{}, appErrors.NewNotAssignedError()
+	}
+
+	var (
+		newReviewerId string
+		reviewerIds   []string
+	)
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		_, err := s.PRDeclineRepo.Save(ctx, domain.PullRequestDecline{
+			PullRequestID: pr.ID,
+			UserID:        declineDTO.ReviewerID,
+			Reason:        declineDTO.Reason,
+		})
+		if err != nil {
+			if errors.Is(appErrors.MapPgError(err), appErrors.ErrAlreadyExists) {
+				return appErrors.NewAlreadyDeclinedError()
+			}
+
 			return err
 		}
 
-		prReviewer := domain.PullRequestReviewer{
-			PullRequestID: prId,
-			UserID:        newReviewerId,
+		currentReviewerIds, err := s.PRReviewerRepo.GetPRUsersIds(ctx, pr.ID)
+		if err != nil {
+			return err
 		}
 
-		_, err = s.PRReviewerRepo.Save(ctx, prReviewer)
+		declinedIds, err := s.PRDeclineRepo.GetPRUsersIds(ctx, pr.ID)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		candidateIds, err := s.reviewerService.SelectReviewers(
+			ctx,
+			pr.AuthorID,
+			pr.TeamID,
+			slices.Concat(currentReviewerIds, declinedIds),
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(candidateIds) < 1 {
+			err = s.PRReviewerRepo.DeleteByPRAndUserId(ctx, pr.ID, declineDTO.ReviewerID)
+			if err != nil {
+				return err
+			}
+
+			err = s.eventRepo.SaveBatch(ctx, []domain.ReviewEvent{
+				domain.NewReviewEvent(dto.EventUnassigned, pr.ID, declineDTO.ReviewerID),
+			})
+		} else {
+			newReviewerId = candidateIds[0]
+			err = s.reviewerService.ReplaceReviewer(ctx, pr.ID, declineDTO.ReviewerID, newReviewerId)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		reviewerIds, err = s.PRReviewerRepo.GetPRUsersIds(ctx, pr.ID)
+
+		return err
 	})
+	if err != nil {
+		return dto.PullRequestDeclineResultDTO{}, err
+	}
+
+	if newReviewerId == "" {
+		metrics.NoCandidate.WithLabelValues(metrics.NoCandidateDecline).Inc()
+	}
 
-	return err
+	return dto.PullRequestDeclineResultDTO{
+		PullRequest: prToDTO(pr, reviewerIds),
+		ReplacedBy:  newReviewerId,
+	}, nil
 }
 
 func (s *pullRequestService) doMerge(
@@ -258,89 +613,114 @@ func (s *pullRequestService) doMerge(
 			return err
 		}
 
+		events := make([]domain.ReviewEvent, 0, len(reviewersIds))
+
 		for _, reviewerId := range reviewersIds {
 			_, err = s.userService.IncrementAssignRate(ctx, reviewerId)
 			if err != nil {
 				return err
 			}
+
+			events = append(events, domain.NewReviewEvent(dto.EventMerged, returnedPr.ID, reviewerId))
 		}
 
 		returnedReviewerIds = reviewersIds
 
-		return nil
+		return s.eventRepo.SaveBatch(ctx, events)
 	})
 	if err != nil {
 		return returnedPr, nil, err
 	}
 
+	metrics.PullRequestsMerged.Inc()
+
 	return returnedPr, returnedReviewerIds, nil
 }
 
-func (s *pullRequestService) getReviewsForUserPR(
-	ctx context.Context,
-	userId string,
-	excludeIds []string,
-) ([]string, error) {
-	user, err := s.userRepo.GetByID(ctx, userId)
-	if err != nil {
-		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
-			return nil, appErrors.NewNotFoundError("User with ID '" + userId + "'")
+// checkPRLead makes sure the user leads the team the PR draws reviewers
+// from: its team context or the author's primary team.
+func (s *pullRequestService) checkPRLead(ctx context.Context, pr domain.PullRequest, userId string) error {
+	teamId := pr.TeamID
+	if !teamId.Valid {
+		author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+		if err != nil {
+			return err
 		}
 
-		return nil, err
+		teamId = author.TeamID
 	}
 
-	usersInTeam, err := s.userRepo.GetByTeamID(ctx, user.TeamID)
-	if err != nil {
-		return nil, err
+	var role dto.TeamRole
+
+	if teamId.Valid {
+		var err error
+
+		role, err = s.userService.GetTeamRole(ctx, userId, teamId.UUID)
+		if err != nil {
+			return err
+		}
 	}
 
-	excludeIds = append(excludeIds, userId)
-	reviewersIds := chooseReviewers(usersInTeam, excludeIds)
+	if role != dto.TeamRoleLead {
+		return appErrors.NewForbiddenError("User '" + userId + "' is not a lead of the pull request team")
+	}
 
-	return reviewersIds, nil
+	return nil
 }
 
-func (s *pullRequestService) prHasReviewer(ctx context.Context, prId string, reviewerId string) (bool, error) {
-	usersIds, err := s.PRReviewerRepo.GetPRUsersIds(ctx, prId)
+// getAuthorTeam resolves the team context of a new PR. The author has to be
+// a member of the team, either through the primary team or an additional
+// membership, and the team must not be archived.
+func (s *pullRequestService) getAuthorTeam(
+	ctx context.Context,
+	author domain.User,
+	teamName string,
+) (domain.Team, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
 	if err != nil {
-		return false, err
-	}
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return domain.Team{}, appErrors.NewNotFoundError("Team with name '" + teamName + "'")
+		}
 
-	if slices.Contains(usersIds, reviewerId) {
-		return true, nil
+		return domain.Team{}, err
 	}
 
-	return false, nil
-}
+	isMember := author.TeamID.Valid && author.TeamID.UUID == team.ID
+	if !isMember {
+		memberships, err := s.membershipRepo.GetByUserID(ctx, author.ID)
+		if err != nil {
+			return domain.Team{}, err
+		}
 
-func chooseReviewers(users []domain.User, excludeIds []string) []string {
-	var probableReviewers []domain.User
+		isMember = slices.ContainsFunc(memberships, func(membership domain.TeamMembership) bool {
+			return membership.TeamID == team.ID
+		})
+	}
 
-	for _, user := range users {
-		if user.IsActive {
-			excluded := slices.Contains(excludeIds, user.ID)
+	if !isMember {
+		return domain.Team{}, appErrors.NewValidationFailedError(
+			"User '" + author.ID + "' is not a member of team '" + team.Name + "'",
+		)
+	}
 
-			if !excluded {
-				probableReviewers = append(probableReviewers, user)
-			}
-		}
+	if team.ArchivedAt != nil {
+		return domain.Team{}, appErrors.NewTeamArchivedError(team.Name)
 	}
 
-	sort.Slice(probableReviewers, func(i, j int) bool {
-		return probableReviewers[i].AssignRate < probableReviewers[j].AssignRate
-	})
+	return team, nil
+}
 
-	if len(probableReviewers) > MAX_REVIEWERS_PER_PR {
-		probableReviewers = probableReviewers[:MAX_REVIEWERS_PER_PR]
+func (s *pullRequestService) prHasReviewer(ctx context.Context, prId string, reviewerId string) (bool, error) {
+	usersIds, err := s.PRReviewerRepo.GetPRUsersIds(ctx, prId)
+	if err != nil {
+		return false, err
 	}
 
-	var reviewers = []string{}
-	for _, reviewer := range probableReviewers {
-		reviewers = append(reviewers, reviewer.ID)
+	if slices.Contains(usersIds, reviewerId) {
+		return true, nil
 	}
 
-	return reviewers
+	return false, nil
 }
 
 func prToDTO(pr domain.PullRequest, reviewerIds []string) dto.PullRequestDTO {
@@ -349,6 +729,7 @@ func prToDTO(pr domain.PullRequest, reviewerIds []string) dto.PullRequestDTO {
 		Name:      pr.Name,
 		AuthorID:  pr.AuthorID,
 		Status:    pr.Status,
+		Priority:  pr.Priority,
 		CreatedAt: pr.CreatedAt,
 		MergedAt:  pr.MergedAt,
 		Reviewers: reviewerIds,
@@ -0,0 +1,601 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sort"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	appErrors "github.com/L11D/avito-review-assign-service/internal/errors"
+	"github.com/L11D/avito-review-assign-service/internal/metrics"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/google/uuid"
+)
+
+const DEFAULT_REVIEWERS_PER_PR = 2
+
+type UserRepoReviewerService interface {
+	GetByID(ctx context.Context, userId string) (domain.User, error)
+	GetByIDs(ctx context.Context, userIds []string) ([]domain.User, error)
+	GetMembersByTeamIDs(ctx context.Context, teamIds []uuid.UUID) ([]domain.TeamMember, error)
+}
+
+type TeamRepoReviewerService interface {
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Team, error)
+}
+
+type TeamSettingsRepoReviewerService interface {
+	GetByTeamIDs(ctx context.Context, teamIds []uuid.UUID) ([]domain.TeamSettings, error)
+}
+
+type PullRequestRepoReviewerService interface {
+	GetOpenByUserIds(ctx context.Context, userIds []string) ([]domain.PullRequest, error)
+}
+
+type PullRequestReviewerRepoReviewerService interface {
+	Save(ctx context.Context, prReviewer domain.PullRequestReviewer) (domain.PullRequestReviewer, error)
+	SaveBatch(ctx context.Context, prReviewers []domain.PullRequestReviewer) error
+	GetByPRIds(ctx context.Context, prIds []string) ([]domain.PullRequestReviewer, error)
+	DeleteByPRAndUserId(ctx context.Context, prId string, userId string) error
+	DeleteBatch(ctx context.Context, prReviewers []domain.PullRequestReviewer) error
+}
+
+type PullRequestDeclineRepoReviewerService interface {
+	GetByPRIds(ctx context.Context, prIds []string) ([]domain.PullRequestDecline, error)
+}
+
+type ReviewEventRepoReviewerService interface {
+	SaveBatch(ctx context.Context, events []domain.ReviewEvent) error
+}
+
+type reviewerService struct {
+	userRepo       UserRepoReviewerService
+	teamRepo       TeamRepoReviewerService
+	settingsRepo   TeamSettingsRepoReviewerService
+	PRRepo         PullRequestRepoReviewerService
+	PRReviewerRepo PullRequestReviewerRepoReviewerService
+	PRDeclineRepo  PullRequestDeclineRepoReviewerService
+	eventRepo      ReviewEventRepoReviewerService
+	fallbackDepth  int
+	trManager      *manager.Manager
+}
+
+func NewReviewerService(
+	userRepo UserRepoReviewerService,
+	teamRepo TeamRepoReviewerService,
+	settingsRepo TeamSettingsRepoReviewerService,
+	prRepo PullRequestRepoReviewerService,
+	prReviewerRepo PullRequestReviewerRepoReviewerService,
+	prDeclineRepo PullRequestDeclineRepoReviewerService,
+	eventRepo ReviewEventRepoReviewerService,
+	fallbackDepth int,
+	trManager *manager.Manager,
+) *reviewerService {
+	return &reviewerService{
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		settingsRepo:   settingsRepo,
+		PRRepo:         prRepo,
+		PRReviewerRepo: prReviewerRepo,
+		PRDeclineRepo:  prDeclineRepo,
+		eventRepo:      eventRepo,
+		fallbackDepth:  fallbackDepth,
+		trManager:      trManager,
+	}
+}
+
+// SelectReviewers returns active members of the PR team picked by the team
+// strategy, skipping the author and every id from excludeIds. An invalid
+// teamId means the author's primary team. When the team has too few
+// candidates, the remaining slots are filled from parent teams, going up at
+// most fallbackDepth levels. Team settings are read on every call, so changes
+// apply to the next assignment.
+func (s *reviewerService) SelectReviewers(
+	ctx context.Context,
+	authorId string,
+	teamId uuid.NullUUID,
+	excludeIds []string,
+) ([]string, error) {
+	author, err := s.userRepo.GetByID(ctx, authorId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return nil, appErrors.NewNotFoundError("User with ID '" + authorId + "'")
+		}
+
+		return nil, err
+	}
+
+	if !teamId.Valid {
+		teamId = author.TeamID
+	}
+
+	if !teamId.Valid {
+		return []string{}, nil
+	}
+
+	settings, err := s.getTeamSettings(ctx, []uuid.UUID{teamId.UUID})
+	if err != nil {
+		return nil, err
+	}
+
+	pools, err := s.getTeamPools(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	teamSettings := settings[teamId.UUID]
+	excludeIds = append(slices.Clone(excludeIds), authorId)
+	reviewers := []string{}
+
+	for _, pool := range pools[teamId.UUID] {
+		candidateIds := chooseReviewers(
+			pool,
+			slices.Concat(excludeIds, reviewers),
+			teamSettings.Strategy,
+			teamSettings.ReviewersCount-len(reviewers),
+		)
+		reviewers = append(reviewers, candidateIds...)
+
+		if len(reviewers) == teamSettings.ReviewersCount {
+			break
+		}
+	}
+
+	return reviewers, nil
+}
+
+// IsEligibleReviewer reports whether the user could have been picked by
+// SelectReviewers for the PR: an active, non-observer member of the PR team
+// or of one of its fallback teams, other than the author.
+func (s *reviewerService) IsEligibleReviewer(
+	ctx context.Context,
+	authorId string,
+	teamId uuid.NullUUID,
+	userId string,
+) (bool, error) {
+	if userId == authorId {
+		return false, nil
+	}
+
+	if !teamId.Valid {
+		author, err := s.userRepo.GetByID(ctx, authorId)
+		if err != nil {
+			return false, err
+		}
+
+		teamId = author.TeamID
+	}
+
+	if !teamId.Valid {
+		return false, nil
+	}
+
+	settings, err := s.getTeamSettings(ctx, []uuid.UUID{teamId.UUID})
+	if err != nil {
+		return false, err
+	}
+
+	pools, err := s.getTeamPools(ctx, settings)
+	if err != nil {
+		return false, err
+	}
+
+	for _, pool := range pools[teamId.UUID] {
+		candidateIds := chooseReviewers(pool, []string{authorId}, settings[teamId.UUID].Strategy, len(pool))
+		if slices.Contains(candidateIds, userId) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetReviewersCount returns how many reviewers the team wants per PR. PRs
+// without a team fall back to the default.
+func (s *reviewerService) GetReviewersCount(ctx context.Context, teamId uuid.NullUUID) (int, error) {
+	if !teamId.Valid {
+		return DEFAULT_REVIEWERS_PER_PR, nil
+	}
+
+	settings, err := s.getTeamSettings(ctx, []uuid.UUID{teamId.UUID})
+	if err != nil {
+		return 0, err
+	}
+
+	return settings[teamId.UUID].ReviewersCount, nil
+}
+
+func defaultTeamSettings(teamId uuid.UUID) domain.TeamSettings {
+	return domain.TeamSettings{
+		TeamID:         teamId,
+		ReviewersCount: DEFAULT_REVIEWERS_PER_PR,
+		Strategy:       dto.ReviewerStrategyLeastLoaded,
+		OwnTeamRule:    dto.OwnTeamPreferred,
+	}
+}
+
+// getTeamSettings returns the settings of every given team, falling back to
+// the defaults for teams that never changed them.
+func (s *reviewerService) getTeamSettings(
+	ctx context.Context,
+	teamIds []uuid.UUID,
+) (map[uuid.UUID]domain.TeamSettings, error) {
+	storedSettings, err := s.settingsRepo.GetByTeamIDs(ctx, teamIds)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[uuid.UUID]domain.TeamSettings, len(teamIds))
+	for _, teamId := range teamIds {
+		settings[teamId] = defaultTeamSettings(teamId)
+	}
+
+	for _, teamSettings := range storedSettings {
+		settings[teamSettings.TeamID] = teamSettings
+	}
+
+	return settings, nil
+}
+
+// getTeamLineage returns the team followed by up to fallbackDepth of its
+// ancestors, nearest first. Archived teams, the own one included, are
+// skipped but still walked through. The own team rule drops either the
+// ancestors or the team itself.
+func (s *reviewerService) getTeamLineage(
+	ctx context.Context,
+	teamId uuid.UUID,
+	ownTeamRule dto.OwnTeamRule,
+) ([]uuid.UUID, error) {
+	maxDepth := s.fallbackDepth
+	if ownTeamRule == dto.OwnTeamOnly {
+		maxDepth = 0
+	}
+
+	lineage := []uuid.UUID{}
+	parentId := uuid.NullUUID{UUID: teamId, Valid: true}
+
+	for depth := 0; depth <= maxDepth && parentId.Valid; depth++ {
+		team, err := s.teamRepo.GetByID(ctx, parentId.UUID)
+		if err != nil {
+			return nil, err
+		}
+
+		if team.ArchivedAt == nil && (depth > 0 || ownTeamRule != dto.OwnTeamExcluded) {
+			lineage = append(lineage, team.ID)
+		}
+
+		parentId = team.ParentID
+	}
+
+	return lineage, nil
+}
+
+func (s *reviewerService) ReplaceReviewer(
+	ctx context.Context,
+	prId string,
+	oldReviewerId string,
+	newReviewerId string,
+) error {
+	return s.trManager.Do(ctx, func(ctx context.Context) error {
+		err := s.PRReviewerRepo.DeleteByPRAndUserId(ctx, prId, oldReviewerId)
+		if err != nil {
+			return err
+		}
+
+		prReviewer := domain.PullRequestReviewer{
+			PullRequestID: prId,
+			UserID:        newReviewerId,
+		}
+
+		_, err = s.PRReviewerRepo.Save(ctx, prReviewer)
+		if err != nil {
+			return err
+		}
+
+		metrics.AfterCommit(ctx, metrics.ReviewerReassignments.Inc)
+
+		return s.eventRepo.SaveBatch(ctx, []domain.ReviewEvent{
+			domain.NewReviewEvent(dto.EventUnassigned, prId, oldReviewerId),
+			domain.NewReviewEvent(dto.EventAssigned, prId, newReviewerId),
+		})
+	})
+}
+
+// ReassignUsersReviews moves every open review of the given users to other
+// members of the PR teams. All PRs are loaded at once and the chosen
+// reviewers are balanced across the batch, so a whole team can be processed
+// in a handful of queries. PRs without a candidate keep their reviewer.
+func (s *reviewerService) ReassignUsersReviews(
+	ctx context.Context,
+	userIds []string,
+) (dto.ReviewsReassignmentReportDTO, error) {
+	report := dto.ReviewsReassignmentReportDTO{
+		Reassigned:  []dto.ReviewReassignmentDTO{},
+		NoCandidate: []dto.ReviewReassignmentDTO{},
+	}
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		metrics.AfterCommit(ctx, func() {
+			metrics.ReviewerReassignments.Add(float64(len(report.Reassigned)))
+			metrics.NoCandidate.WithLabelValues(metrics.NoCandidateBatch).Add(float64(len(report.NoCandidate)))
+		})
+
+		prs, err := s.PRRepo.GetOpenByUserIds(ctx, userIds)
+		if err != nil || len(prs) == 0 {
+			return err
+		}
+
+		prIds := make([]string, len(prs))
+		authorIds := make([]string, len(prs))
+
+		for i, pr := range prs {
+			prIds[i] = pr.ID
+			authorIds[i] = pr.AuthorID
+		}
+
+		prReviewers, err := s.PRReviewerRepo.GetByPRIds(ctx, prIds)
+		if err != nil {
+			return err
+		}
+
+		reviewersByPR := make(map[string][]string, len(prs))
+		for _, prReviewer := range prReviewers {
+			reviewersByPR[prReviewer.PullRequestID] = append(
+				reviewersByPR[prReviewer.PullRequestID],
+				prReviewer.UserID,
+			)
+		}
+
+		declines, err := s.PRDeclineRepo.GetByPRIds(ctx, prIds)
+		if err != nil {
+			return err
+		}
+
+		declinedByPR := make(map[string][]string)
+		for _, decline := range declines {
+			declinedByPR[decline.PullRequestID] = append(declinedByPR[decline.PullRequestID], decline.UserID)
+		}
+
+		prTeamIds, err := s.getPRTeamIds(ctx, prs, authorIds)
+		if err != nil {
+			return err
+		}
+
+		settings, err := s.getTeamSettings(ctx, slices.Collect(maps.Values(prTeamIds)))
+		if err != nil {
+			return err
+		}
+
+		pools, err := s.getTeamPools(ctx, settings)
+		if err != nil {
+			return err
+		}
+
+		var (
+			toDelete []domain.PullRequestReviewer
+			toSave   []domain.PullRequestReviewer
+		)
+
+		pendingLoad := make(map[string]int)
+
+		for _, pr := range prs {
+			for _, reviewerId := range reviewersByPR[pr.ID] {
+				if !slices.Contains(userIds, reviewerId) {
+					continue
+				}
+
+				reassignment := dto.ReviewReassignmentDTO{
+					PullRequestID: pr.ID,
+					OldReviewerID: reviewerId,
+				}
+
+				excludeIds := slices.Concat(
+					reviewersByPR[pr.ID],
+					declinedByPR[pr.ID],
+					userIds,
+					[]string{pr.AuthorID},
+				)
+
+				var candidateIds []string
+				for _, pool := range pools[prTeamIds[pr.ID]] {
+					candidateIds = chooseReviewers(
+						withPendingLoad(pool, pendingLoad),
+						excludeIds,
+						settings[prTeamIds[pr.ID]].Strategy,
+						1,
+					)
+					if len(candidateIds) > 0 {
+						break
+					}
+				}
+
+				if len(candidateIds) < 1 {
+					report.NoCandidate = append(report.NoCandidate, reassignment)
+
+					continue
+				}
+
+				newReviewerId := candidateIds[0]
+				pendingLoad[newReviewerId]++
+				reviewersByPR[pr.ID] = append(reviewersByPR[pr.ID], newReviewerId)
+
+				toDelete = append(toDelete, domain.PullRequestReviewer{PullRequestID: pr.ID, UserID: reviewerId})
+				toSave = append(toSave, domain.PullRequestReviewer{PullRequestID: pr.ID, UserID: newReviewerId})
+
+				reassignment.NewReviewerID = newReviewerId
+				report.Reassigned = append(report.Reassigned, reassignment)
+			}
+		}
+
+		if len(toDelete) == 0 {
+			return nil
+		}
+
+		err = s.PRReviewerRepo.DeleteBatch(ctx, toDelete)
+		if err != nil {
+			return err
+		}
+
+		err = s.PRReviewerRepo.SaveBatch(ctx, toSave)
+		if err != nil {
+			return err
+		}
+
+		events := make([]domain.ReviewEvent, 0, len(toDelete)+len(toSave))
+		for _, prReviewer := range toDelete {
+			events = append(events, domain.NewReviewEvent(dto.EventUnassigned, prReviewer.PullRequestID, prReviewer.UserID))
+		}
+
+		for _, prReviewer := range toSave {
+			events = append(events, domain.NewReviewEvent(dto.EventAssigned, prReviewer.PullRequestID, prReviewer.UserID))
+		}
+
+		return s.eventRepo.SaveBatch(ctx, events)
+	})
+	if err != nil {
+		return dto.ReviewsReassignmentReportDTO{}, err
+	}
+
+	return report, nil
+}
+
+// getPRTeamIds returns the team each PR draws reviewers from: the team
+// context it was created with, or the author's primary team. PRs of authors
+// without a team are left out.
+func (s *reviewerService) getPRTeamIds(
+	ctx context.Context,
+	prs []domain.PullRequest,
+	authorIds []string,
+) (map[string]uuid.UUID, error) {
+	authors, err := s.userRepo.GetByIDs(ctx, authorIds)
+	if err != nil {
+		return nil, err
+	}
+
+	authorTeamIds := make(map[string]uuid.NullUUID, len(authors))
+	for _, author := range authors {
+		authorTeamIds[author.ID] = author.TeamID
+	}
+
+	prTeamIds := make(map[string]uuid.UUID, len(prs))
+
+	for _, pr := range prs {
+		teamId := pr.TeamID
+		if !teamId.Valid {
+			teamId = authorTeamIds[pr.AuthorID]
+		}
+
+		if teamId.Valid {
+			prTeamIds[pr.ID] = teamId.UUID
+		}
+	}
+
+	return prTeamIds, nil
+}
+
+// getTeamPools returns, keyed by team id, the members of the team followed by
+// the members of each fallback ancestor team, as allowed by the team
+// settings. Lineages are resolved once per team and all members load in one
+// query.
+func (s *reviewerService) getTeamPools(
+	ctx context.Context,
+	settings map[uuid.UUID]domain.TeamSettings,
+) (map[uuid.UUID][][]domain.User, error) {
+	lineages := make(map[uuid.UUID][]uuid.UUID, len(settings))
+	poolTeamIds := make([]uuid.UUID, 0, len(settings))
+
+	for teamId, teamSettings := range settings {
+		lineage, err := s.getTeamLineage(ctx, teamId, teamSettings.OwnTeamRule)
+		if err != nil {
+			return nil, err
+		}
+
+		lineages[teamId] = lineage
+
+		for _, lineageTeamId := range lineage {
+			if !slices.Contains(poolTeamIds, lineageTeamId) {
+				poolTeamIds = append(poolTeamIds, lineageTeamId)
+			}
+		}
+	}
+
+	members, err := s.userRepo.GetMembersByTeamIDs(ctx, poolTeamIds)
+	if err != nil {
+		return nil, err
+	}
+
+	usersByTeam := make(map[uuid.UUID][]domain.User, len(poolTeamIds))
+	for _, member := range members {
+		usersByTeam[member.PoolTeamID] = append(usersByTeam[member.PoolTeamID], member.User)
+	}
+
+	pools := make(map[uuid.UUID][][]domain.User, len(lineages))
+	for teamId, lineage := range lineages {
+		for _, lineageTeamId := range lineage {
+			pools[teamId] = append(pools[teamId], usersByTeam[lineageTeamId])
+		}
+	}
+
+	return pools, nil
+}
+
+// withPendingLoad returns copies of users whose assign_rate also counts the
+// reviews handed to them earlier in the same batch.
+func withPendingLoad(users []domain.User, pendingLoad map[string]int) []domain.User {
+	loaded := make([]domain.User, len(users))
+	for i, user := range users {
+		user.AssignRate += pendingLoad[user.ID]
+		loaded[i] = user
+	}
+
+	return loaded
+}
+
+// chooseReviewers returns up to limit distinct eligible users, either the
+// least loaded ones or a random selection.
+func chooseReviewers(
+	users []domain.User,
+	excludeIds []string,
+	strategy dto.ReviewerStrategy,
+	limit int,
+) []string {
+	var probableReviewers []domain.User
+
+	for _, user := range users {
+		if user.IsActive && user.Role != dto.TeamRoleObserver {
+			excluded := slices.Contains(excludeIds, user.ID)
+			duplicate := slices.ContainsFunc(probableReviewers, func(reviewer domain.User) bool {
+				return reviewer.ID == user.ID
+			})
+
+			if !excluded && !duplicate {
+				probableReviewers = append(probableReviewers, user)
+			}
+		}
+	}
+
+	if strategy == dto.ReviewerStrategyRandom {
+		rand.Shuffle(len(probableReviewers), func(i, j int) {
+			probableReviewers[i], probableReviewers[j] = probableReviewers[j], probableReviewers[i]
+		})
+	} else {
+		sort.Slice(probableReviewers, func(i, j int) bool {
+			return probableReviewers[i].AssignRate < probableReviewers[j].AssignRate
+		})
+	}
+
+	if len(probableReviewers) > limit {
+		probableReviewers = probableReviewers[:max(limit, 0)]
+	}
+
+	var reviewers = []string{}
+	for _, reviewer := range probableReviewers {
+		reviewers = append(reviewers, reviewer.ID)
+	}
+
+	return reviewers
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/L11D/avito-review-assign-service/internal/domain"
+	appErrors "github.com/L11D/avito-review-assign-service/internal/errors"
+	"github.com/L11D/avito-review-assign-service/internal/metrics"
+	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/google/uuid"
+)
+
+type TeamRepoSLAService interface {
+	GetByName(ctx context.Context, name string) (domain.Team, error)
+}
+
+type TeamSLAPolicyRepo interface {
+	Upsert(ctx context.Context, policy domain.TeamSLAPolicy) (domain.TeamSLAPolicy, error)
+	GetByTeamID(ctx context.Context, teamId uuid.UUID) (domain.TeamSLAPolicy, error)
+}
+
+type PullRequestReviewerRepoSLAService interface {
+	GetPRUsersIds(ctx context.Context, prId string) ([]string, error)
+	GetOverdue(ctx context.Context, teamId *uuid.UUID, onlyUnhandled bool) ([]domain.OverdueReview, error)
+	MarkSLABreached(ctx context.Context, prId string, userId string) error
+}
+
+type PullRequestDeclineRepoSLAService interface {
+	GetPRUsersIds(ctx context.Context, prId string) ([]string, error)
+}
+
+type ReviewSLAEventRepo interface {
+	Save(ctx context.Context, event domain.ReviewSLAEvent) (domain.ReviewSLAEvent, error)
+}
+
+type UserRepoSLAService interface {
+	GetByID(ctx context.Context, userId string) (domain.User, error)
+}
+
+type ReviewerServiceSLAService interface {
+	SelectReviewers(
+		ctx context.Context,
+		authorId string,
+		teamId uuid.NullUUID,
+		excludeIds []string,
+	) ([]string, error)
+	ReplaceReviewer(ctx context.Context, prId string, oldReviewerId string, newReviewerId string) error
+}
+
+type slaService struct {
+	teamRepo        TeamRepoSLAService
+	policyRepo      TeamSLAPolicyRepo
+	PRReviewerRepo  PullRequestReviewerRepoSLAService
+	PRDeclineRepo   PullRequestDeclineRepoSLAService
+	eventRepo       ReviewSLAEventRepo
+	userRepo        UserRepoSLAService
+	reviewerService ReviewerServiceSLAService
+	trManager       *manager.Manager
+}
+
+func NewSLAService(
+	teamRepo TeamRepoSLAService,
+	policyRepo TeamSLAPolicyRepo,
+	prReviewerRepo PullRequestReviewerRepoSLAService,
+	prDeclineRepo PullRequestDeclineRepoSLAService,
+	eventRepo ReviewSLAEventRepo,
+	userRepo UserRepoSLAService,
+	reviewerService ReviewerServiceSLAService,
+	trManager *manager.Manager,
+) *slaService {
+	return &slaService{
+		teamRepo:        teamRepo,
+		policyRepo:      policyRepo,
+		PRReviewerRepo:  prReviewerRepo,
+		PRDeclineRepo:   prDeclineRepo,
+		eventRepo:       eventRepo,
+		userRepo:        userRepo,
+		reviewerService: reviewerService,
+		trManager:       trManager,
+	}
+}
+
+func (s *slaService) SetTeamPolicy(ctx context.Context, policyDTO dto.TeamSLAPolicyDTO) (dto.TeamSLAPolicyDTO, error) {
+	reviewSLA, err := time.ParseDuration(policyDTO.ReviewSLA)
+	if err != nil || reviewSLA < time.Second {
+		return dto.TeamSLAPolicyDTO{}, appErrors.NewValidationFailedError(
+			"review_sla must be a duration of at least 1s, got '" + policyDTO.ReviewSLA + "'",
+		)
+	}
+
+	if policyDTO.Action == dto.SLAActionEscalate && policyDTO.BackupReviewerID == nil {
+		return dto.TeamSLAPolicyDTO{}, appErrors.NewValidationFailedError(
+			"backup_reviewer_id is required for the ESCALATE action",
+		)
+	}
+
+	team, err := s.getTeam(ctx, policyDTO.TeamName)
+	if err != nil {
+		return dto.TeamSLAPolicyDTO{}, err
+	}
+
+	if policyDTO.BackupReviewerID != nil {
+		_, err = s.userRepo.GetByID(ctx, *policyDTO.BackupReviewerID)
+		if err != nil {
+			if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+				return dto.TeamSLAPolicyDTO{},
+					appErrors.NewNotFoundError("User with ID '" + *policyDTO.BackupReviewerID + "'")
+			}
+
+			return dto.TeamSLAPolicyDTO{}, err
+		}
+	}
+
+	policy, err := s.policyRepo.Upsert(ctx, domain.TeamSLAPolicy{
+		TeamID:           team.ID,
+		ReviewSLASeconds: int64(reviewSLA / time.Second),
+		Action:           policyDTO.Action,
+		BackupReviewerID: policyDTO.BackupReviewerID,
+	})
+	if err != nil {
+		return dto.TeamSLAPolicyDTO{}, err
+	}
+
+	return policyToDTO(team.Name, policy), nil
+}
+
+func (s *slaService) GetTeamPolicy(ctx context.Context, teamName string) (dto.TeamSLAPolicyDTO, error) {
+	team, err := s.getTeam(ctx, teamName)
+	if err != nil {
+		return dto.TeamSLAPolicyDTO{}, err
+	}
+
+	policy, err := s.policyRepo.GetByTeamID(ctx, team.ID)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.TeamSLAPolicyDTO{}, appErrors.NewNotFoundError("SLA policy for team '" + teamName + "'")
+		}
+
+		return dto.TeamSLAPolicyDTO{}, err
+	}
+
+	return policyToDTO(team.Name, policy), nil
+}
+
+// GetOverdueReviews lists every open assignment past its team SLA, including
+// the ones the worker could not hand over to anybody. An empty teamName means
+// all teams.
+func (s *slaService) GetOverdueReviews(ctx context.Context, teamName string) (dto.OverdueReviewsDTO, error) {
+	var teamId *uuid.UUID
+
+	if teamName != "" {
+		team, err := s.getTeam(ctx, teamName)
+		if err != nil {
+			return dto.OverdueReviewsDTO{}, err
+		}
+
+		teamId = &team.ID
+	}
+
+	overdueReviews, err := s.PRReviewerRepo.GetOverdue(ctx, teamId, false)
+	if err != nil {
+		return dto.OverdueReviewsDTO{}, err
+	}
+
+	overdueDTOs := make([]dto.OverdueReviewDTO, len(overdueReviews))
+	for i, review := range overdueReviews {
+		overdueDTOs[i] = dto.OverdueReviewDTO{
+			PullRequestID:   review.PullRequestID,
+			PullRequestName: review.PullRequestName,
+			AuthorID:        review.AuthorID,
+			ReviewerID:      review.ReviewerID,
+			AssignedAt:      review.AssignedAt,
+			Deadline:        review.Deadline,
+			SLABreachedAt:   review.SLABreachedAt,
+		}
+	}
+
+	return dto.OverdueReviewsDTO{
+		OverdueReviews: overdueDTOs,
+	}, nil
+}
+
+// ProcessOverdueReviews applies the team policy to every assignment that
+// became overdue since the previous run and returns how many were handled.
+func (s *slaService) ProcessOverdueReviews(ctx context.Context) (int, error) {
+	overdueReviews, err := s.PRReviewerRepo.GetOverdue(ctx, nil, true)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+
+	for _, review := range overdueReviews {
+		err = s.processOverdueReview(ctx, review)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to process overdue review",
+				slog.String("pull_request_id", review.PullRequestID),
+				slog.String("reviewer_id", review.ReviewerID),
+				slog.String("error", err.Error()),
+			)
+
+			continue
+		}
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (s *slaService) processOverdueReview(ctx context.Context, review domain.OverdueReview) error {
+	return s.trManager.Do(ctx, func(ctx context.Context) error {
+		reviewerIds, err := s.PRReviewerRepo.GetPRUsersIds(ctx, review.PullRequestID)
+		if err != nil {
+			return err
+		}
+
+		declinedIds, err := s.PRDeclineRepo.GetPRUsersIds(ctx, review.PullRequestID)
+		if err != nil {
+			return err
+		}
+
+		excludeIds := slices.Concat(reviewerIds, declinedIds)
+
+		var (
+			newReviewerId string
+			action        dto.SLAEventAction
+		)
+
+		switch review.Action {
+		case dto.SLAActionEscalate:
+			eligible, err := s.isEligibleBackup(ctx, review, excludeIds)
+			if err != nil {
+				return err
+			}
+
+			if eligible {
+				newReviewerId = *review.BackupReviewerID
+				action = dto.SLAEventEscalated
+			}
+		case dto.SLAActionReassign:
+			candidateIds, err := s.reviewerService.SelectReviewers(
+				ctx,
+				review.AuthorID,
+				uuid.NullUUID{UUID: review.TeamID, Valid: true},
+				excludeIds,
+			)
+			if err != nil {
+				return err
+			}
+
+			if len(candidateIds) > 0 {
+				newReviewerId = candidateIds[0]
+				action = dto.SLAEventReassigned
+			}
+		}
+
+		event := domain.ReviewSLAEvent{
+			PullRequestID: review.PullRequestID,
+			ReviewerID:    review.ReviewerID,
+		}
+
+		if newReviewerId == "" {
+			event.Action = dto.SLAEventNoCandidate
+			metrics.AfterCommit(ctx, metrics.NoCandidate.WithLabelValues(metrics.NoCandidateSLA).Inc)
+
+			err = s.PRReviewerRepo.MarkSLABreached(ctx, review.PullRequestID, review.ReviewerID)
+		} else {
+			event.Action = action
+			event.NewReviewerID = &newReviewerId
+
+			err = s.reviewerService.ReplaceReviewer(ctx, review.PullRequestID, review.ReviewerID, newReviewerId)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = s.eventRepo.Save(ctx, event)
+
+		return err
+	})
+}
+
+func (s *slaService) isEligibleBackup(
+	ctx context.Context,
+	review domain.OverdueReview,
+	excludeIds []string,
+) (bool, error) {
+	if review.BackupReviewerID == nil ||
+		*review.BackupReviewerID == review.AuthorID ||
+		slices.Contains(excludeIds, *review.BackupReviewerID) {
+		return false, nil
+	}
+
+	backup, err := s.userRepo.GetByID(ctx, *review.BackupReviewerID)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return backup.IsActive, nil
+}
+
+func (s *slaService) getTeam(ctx context.Context, teamName string) (domain.Team, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return domain.Team{}, appErrors.NewNotFoundError("Team with name '" + teamName + "'")
+		}
+
+		return domain.Team{}, err
+	}
+
+	return team, nil
+}
+
+func policyToDTO(teamName string, policy domain.TeamSLAPolicy) dto.TeamSLAPolicyDTO {
+	return dto.TeamSLAPolicyDTO{
+		TeamName:         teamName,
+		ReviewSLA:        policy.ReviewSLA().String(),
+		Action:           policy.Action,
+		BackupReviewerID: policy.BackupReviewerID,
+	}
+}
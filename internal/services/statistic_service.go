@@ -2,51 +2,624 @@ package services
 
 import (
 	"context"
-	"sort"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"time"
 
 	"github.com/L11D/avito-review-assign-service/internal/domain"
+	appErrors "github.com/L11D/avito-review-assign-service/internal/errors"
 	"github.com/L11D/avito-review-assign-service/pkg/api/dto"
 	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/google/uuid"
 )
 
-type UserRepoStatistic interface {
-	GetAll(ctx context.Context) ([]domain.User, error)
+type PullRequestDeclineRepoStatistic interface {
+	EachGroupedByUser(ctx context.Context, fn func(domain.PullRequestDecline) error) error
+}
+
+type StatisticRepo interface {
+	EachUserStatistic(
+		ctx context.Context,
+		filter domain.UserStatisticFilter,
+		fn func(domain.UserStatistic) error,
+	) error
+	GetTeamsStatistic(ctx context.Context, filter domain.TeamStatisticFilter) ([]domain.TeamStatistic, error)
+	GetTeamMembersStatistic(ctx context.Context, teamId uuid.UUID) ([]domain.TeamMemberStatistic, error)
+	GetActivity(ctx context.Context, filter domain.ActivityFilter) ([]domain.ActivityPoint, error)
+	GetLifecycleStatistic(ctx context.Context, filter domain.LifecycleFilter) ([]domain.LifecycleStatistic, error)
+	GetReviewLoads(ctx context.Context, from *time.Time, to *time.Time) ([]domain.ReviewLoad, error)
+	GetReviewGraph(ctx context.Context, filter domain.ReviewGraphFilter) ([]domain.ReviewEdge, error)
+}
+
+type TeamRepoStatistic interface {
+	GetByName(ctx context.Context, name string) (domain.Team, error)
 }
 
 type statisticService struct {
-	userRepo  UserRepoStatistic
-	trManager *manager.Manager
+	PRDeclineRepo     PullRequestDeclineRepoStatistic
+	statisticRepo     StatisticRepo
+	teamRepo          TeamRepoStatistic
+	fairnessThreshold float64
+	trManager         *manager.Manager
 }
 
 func NewStatisticService(
-	userRepo UserRepoStatistic,
+	prDeclineRepo PullRequestDeclineRepoStatistic,
+	statisticRepo StatisticRepo,
+	teamRepo TeamRepoStatistic,
+	fairnessThreshold float64,
 	trManager *manager.Manager,
 ) *statisticService {
 	return &statisticService{
-		userRepo:  userRepo,
-		trManager: trManager,
+		PRDeclineRepo:     prDeclineRepo,
+		statisticRepo:     statisticRepo,
+		teamRepo:          teamRepo,
+		fairnessThreshold: fairnessThreshold,
+		trManager:         trManager,
 	}
 }
 
-func (s *statisticService) GetUsersStatistic(ctx context.Context) (dto.AllUsersStatisticDTO, error) {
-	users, err := s.userRepo.GetAll(ctx)
+// GetUsersStatistic ranks users by their lifetime assign_rate. When a period
+// is requested, every user also gets the activity from review events and the
+// ranking is done by merged reviews in that period instead.
+func (s *statisticService) GetUsersStatistic(
+	ctx context.Context,
+	query dto.StatisticPeriodQueryDTO,
+) (dto.AllUsersStatisticDTO, error) {
+	userStatistics := []dto.UserStatisticDTO{}
+
+	err := s.StreamUsersStatistic(ctx, query, func(statistic dto.UserStatisticDTO) error {
+		userStatistics = append(userStatistics, statistic)
+
+		return nil
+	})
 	if err != nil {
 		return dto.AllUsersStatisticDTO{}, err
 	}
 
-	userStatistics := make([]dto.UserStatisticDTO, 0, len(users))
-	for _, user := range users {
-		userStatistics = append(userStatistics, dto.UserStatisticDTO{
-			UserID:     user.ID,
+	return dto.AllUsersStatisticDTO{
+		Statistics: userStatistics,
+	}, nil
+}
+
+// StreamUsersStatistic passes the users to emit one by one in the order of
+// GetUsersStatistic. Only the activity series are loaded up front, and only
+// when grouping by period is requested.
+func (s *statisticService) StreamUsersStatistic(
+	ctx context.Context,
+	query dto.StatisticPeriodQueryDTO,
+	emit func(dto.UserStatisticDTO) error,
+) error {
+	err := validatePeriod(query)
+	if err != nil {
+		return err
+	}
+
+	var series map[string]*dto.ActivityDTO
+
+	if query.GroupBy != "" {
+		series, err = s.getActivities(ctx, query, domain.ActivityFilter{})
+		if err != nil {
+			return err
+		}
+	}
+
+	filter := domain.UserStatisticFilter{
+		From:         query.From,
+		To:           query.To,
+		WithActivity: query.From != nil || query.To != nil || query.GroupBy != "",
+	}
+
+	return s.statisticRepo.EachUserStatistic(ctx, filter, func(user domain.UserStatistic) error {
+		statistic := dto.UserStatisticDTO{
+			UserID:     user.UserID,
 			AssignRate: user.AssignRate,
+		}
+
+		if filter.WithActivity {
+			statistic.Activity = &dto.ActivityDTO{
+				Assigned:   user.Assigned,
+				Unassigned: user.Unassigned,
+				Merged:     user.Merged,
+			}
+
+			if activity, ok := series[user.UserID]; ok {
+				statistic.Activity.Series = activity.Series
+			}
+		}
+
+		return emit(statistic)
+	})
+}
+
+func (s *statisticService) GetDeclinesStatistic(ctx context.Context) (dto.AllDeclinesStatisticDTO, error) {
+	declineStatistics := []dto.UserDeclineStatisticDTO{}
+
+	err := s.StreamDeclinesStatistic(ctx, func(statistic dto.UserDeclineStatisticDTO) error {
+		declineStatistics = append(declineStatistics, statistic)
+
+		return nil
+	})
+	if err != nil {
+		return dto.AllDeclinesStatisticDTO{}, err
+	}
+
+	return dto.AllDeclinesStatisticDTO{
+		Statistics: declineStatistics,
+	}, nil
+}
+
+// StreamDeclinesStatistic passes the declines of every user to emit, users
+// with more declines first. Only the declines of one user are kept in memory.
+func (s *statisticService) StreamDeclinesStatistic(
+	ctx context.Context,
+	emit func(dto.UserDeclineStatisticDTO) error,
+) error {
+	var current *dto.UserDeclineStatisticDTO
+
+	err := s.PRDeclineRepo.EachGroupedByUser(ctx, func(decline domain.PullRequestDecline) error {
+		if current != nil && current.UserID != decline.UserID {
+			err := emit(*current)
+			if err != nil {
+				return err
+			}
+
+			current = nil
+		}
+
+		if current == nil {
+			current = &dto.UserDeclineStatisticDTO{
+				UserID:   decline.UserID,
+				Declines: []dto.DeclineDTO{},
+			}
+		}
+
+		current.DeclineCount++
+		current.Declines = append(current.Declines, dto.DeclineDTO{
+			PullRequestID: decline.PullRequestID,
+			Reason:        decline.Reason,
+			DeclinedAt:    decline.DeclinedAt,
 		})
+
+		return nil
+	})
+	if err != nil || current == nil {
+		return err
+	}
+
+	return emit(*current)
+}
+
+func (s *statisticService) GetTeamsStatistic(
+	ctx context.Context,
+	query dto.TeamsStatisticQueryDTO,
+) (dto.AllTeamsStatisticDTO, error) {
+	err := validatePeriod(query.StatisticPeriodQueryDTO)
+	if err != nil {
+		return dto.AllTeamsStatisticDTO{}, err
 	}
 
-	sort.Slice(userStatistics, func(i, j int) bool {
-		return userStatistics[i].AssignRate > userStatistics[j].AssignRate
+	statistics, err := s.statisticRepo.GetTeamsStatistic(ctx, domain.TeamStatisticFilter{
+		IncludeArchived: query.IncludeArchived,
 	})
+	if err != nil {
+		return dto.AllTeamsStatisticDTO{}, err
+	}
 
-	return dto.AllUsersStatisticDTO{
-		Statistics: userStatistics,
+	activities, err := s.getActivities(ctx, query.StatisticPeriodQueryDTO, domain.ActivityFilter{ByTeam: true})
+	if err != nil {
+		return dto.AllTeamsStatisticDTO{}, err
+	}
+
+	teamStatistics := make([]dto.TeamStatisticDTO, len(statistics))
+	for i, statistic := range statistics {
+		teamStatistics[i] = teamStatisticToDTO(statistic)
+		teamStatistics[i].Activity = activityOf(activities, statistic.TeamID.String())
+	}
+
+	return dto.AllTeamsStatisticDTO{
+		Statistics: teamStatistics,
+	}, nil
+}
+
+func (s *statisticService) GetTeamStatistic(
+	ctx context.Context,
+	teamName string,
+	query dto.StatisticPeriodQueryDTO,
+) (dto.TeamDetailedStatisticDTO, error) {
+	err := validatePeriod(query)
+	if err != nil {
+		return dto.TeamDetailedStatisticDTO{}, err
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.TeamDetailedStatisticDTO{}, appErrors.NewNotFoundError("Team with name '" + teamName + "'")
+		}
+
+		return dto.TeamDetailedStatisticDTO{}, err
+	}
+
+	statistics, err := s.statisticRepo.GetTeamsStatistic(ctx, domain.TeamStatisticFilter{
+		TeamID:          uuid.NullUUID{UUID: team.ID, Valid: true},
+		IncludeArchived: true,
+	})
+	if err != nil {
+		return dto.TeamDetailedStatisticDTO{}, err
+	}
+
+	if len(statistics) == 0 {
+		return dto.TeamDetailedStatisticDTO{}, appErrors.NewNotFoundError("Team with name '" + teamName + "'")
+	}
+
+	memberStatistics, err := s.statisticRepo.GetTeamMembersStatistic(ctx, team.ID)
+	if err != nil {
+		return dto.TeamDetailedStatisticDTO{}, err
+	}
+
+	teamActivities, err := s.getActivities(ctx, query, domain.ActivityFilter{
+		ByTeam: true,
+		TeamID: uuid.NullUUID{UUID: team.ID, Valid: true},
+	})
+	if err != nil {
+		return dto.TeamDetailedStatisticDTO{}, err
+	}
+
+	memberIds := make([]string, len(memberStatistics))
+	for i, member := range memberStatistics {
+		memberIds[i] = member.UserID
+	}
+
+	memberActivities, err := s.getActivities(ctx, query, domain.ActivityFilter{UserIDs: memberIds})
+	if err != nil {
+		return dto.TeamDetailedStatisticDTO{}, err
+	}
+
+	members := make([]dto.TeamMemberStatisticDTO, len(memberStatistics))
+	for i, member := range memberStatistics {
+		members[i] = dto.TeamMemberStatisticDTO{
+			UserID:          member.UserID,
+			Username:        member.Username,
+			Role:            member.Role,
+			IsActive:        member.IsActive,
+			AssignRate:      member.AssignRate,
+			PRsCreated:      member.PRsCreated,
+			PRsMerged:       member.PRsMerged,
+			PRsOpen:         member.PRsOpen,
+			ReviewsAssigned: member.ReviewsAssigned,
+			OpenReviews:     member.OpenReviews,
+			Activity:        activityOf(memberActivities, member.UserID),
+		}
+	}
+
+	teamStatistic := teamStatisticToDTO(statistics[0])
+	teamStatistic.Activity = activityOf(teamActivities, team.ID.String())
+
+	return dto.TeamDetailedStatisticDTO{
+		TeamStatisticDTO: teamStatistic,
+		Members:          members,
+	}, nil
+}
+
+// GetPRLifecycleStatistic reports how fast PRs get merged and how stable their
+// reviewer sets are, overall, per team and per author.
+func (s *statisticService) GetPRLifecycleStatistic(
+	ctx context.Context,
+	query dto.PRLifecycleQueryDTO,
+) (dto.AllPRLifecycleStatisticDTO, error) {
+	err := validatePeriod(dto.StatisticPeriodQueryDTO{From: query.From, To: query.To})
+	if err != nil {
+		return dto.AllPRLifecycleStatisticDTO{}, err
+	}
+
+	filter := domain.LifecycleFilter{From: query.From, To: query.To}
+
+	overall, err := s.statisticRepo.GetLifecycleStatistic(ctx, filter)
+	if err != nil {
+		return dto.AllPRLifecycleStatisticDTO{}, err
+	}
+
+	filter.Grouping = domain.LifecycleByTeam
+
+	teams, err := s.statisticRepo.GetLifecycleStatistic(ctx, filter)
+	if err != nil {
+		return dto.AllPRLifecycleStatisticDTO{}, err
+	}
+
+	filter.Grouping = domain.LifecycleByAuthor
+
+	authors, err := s.statisticRepo.GetLifecycleStatistic(ctx, filter)
+	if err != nil {
+		return dto.AllPRLifecycleStatisticDTO{}, err
+	}
+
+	result := dto.AllPRLifecycleStatisticDTO{
+		Teams:   make([]dto.TeamPRLifecycleStatisticDTO, len(teams)),
+		Authors: make([]dto.AuthorPRLifecycleStatisticDTO, len(authors)),
+	}
+
+	if len(overall) > 0 {
+		result.Overall = lifecycleStatisticToDTO(overall[0])
+	}
+
+	for i, team := range teams {
+		result.Teams[i] = dto.TeamPRLifecycleStatisticDTO{
+			PRLifecycleStatisticDTO: lifecycleStatisticToDTO(team),
+			TeamName:                team.Key,
+		}
+	}
+
+	for i, author := range authors {
+		result.Authors[i] = dto.AuthorPRLifecycleStatisticDTO{
+			PRLifecycleStatisticDTO: lifecycleStatisticToDTO(author),
+			AuthorID:                author.Key,
+		}
+	}
+
+	return result, nil
+}
+
+// GetFairnessStatistic measures how evenly reviews are spread inside every
+// team. A team is flagged as unfair when its Gini coefficient is above the
+// threshold.
+func (s *statisticService) GetFairnessStatistic(
+	ctx context.Context,
+	query dto.FairnessQueryDTO,
+) (dto.AllTeamsFairnessDTO, error) {
+	err := validatePeriod(dto.StatisticPeriodQueryDTO{From: query.From, To: query.To})
+	if err != nil {
+		return dto.AllTeamsFairnessDTO{}, err
+	}
+
+	threshold := s.fairnessThreshold
+	if query.Threshold != nil {
+		threshold = *query.Threshold
+	}
+
+	loads, err := s.statisticRepo.GetReviewLoads(ctx, query.From, query.To)
+	if err != nil {
+		return dto.AllTeamsFairnessDTO{}, err
+	}
+
+	teams := []dto.TeamFairnessDTO{}
+
+	for i := 0; i < len(loads); {
+		j := i
+		teamLoads := []int{}
+
+		for ; j < len(loads) && loads[j].TeamID == loads[i].TeamID; j++ {
+			teamLoads = append(teamLoads, loads[j].Load)
+		}
+
+		fairness := teamFairness(teamLoads)
+		fairness.TeamName = loads[i].TeamName
+		fairness.Unfair = fairness.Gini > threshold
+		teams = append(teams, fairness)
+
+		i = j
+	}
+
+	return dto.AllTeamsFairnessDTO{
+		Threshold: threshold,
+		Teams:     teams,
 	}, nil
 }
+
+// GetReviewGraph returns who reviews whom: an edge from every reviewer to
+// every author they review, weighted by the number of PRs. Users without
+// reviews in the selection are not part of the graph.
+func (s *statisticService) GetReviewGraph(
+	ctx context.Context,
+	query dto.ReviewGraphQueryDTO,
+) (dto.ReviewGraphDTO, error) {
+	err := validatePeriod(dto.StatisticPeriodQueryDTO{From: query.From, To: query.To})
+	if err != nil {
+		return dto.ReviewGraphDTO{}, err
+	}
+
+	filter := domain.ReviewGraphFilter{From: query.From, To: query.To}
+
+	if query.TeamName != "" {
+		team, err := s.teamRepo.GetByName(ctx, query.TeamName)
+		if err != nil {
+			if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+				return dto.ReviewGraphDTO{}, appErrors.NewNotFoundError("Team with name '" + query.TeamName + "'")
+			}
+
+			return dto.ReviewGraphDTO{}, err
+		}
+
+		filter.TeamID = uuid.NullUUID{UUID: team.ID, Valid: true}
+	}
+
+	edges, err := s.statisticRepo.GetReviewGraph(ctx, filter)
+	if err != nil {
+		return dto.ReviewGraphDTO{}, err
+	}
+
+	graph := dto.ReviewGraphDTO{
+		Nodes: []dto.ReviewGraphNodeDTO{},
+		Edges: make([]dto.ReviewGraphEdgeDTO, len(edges)),
+	}
+
+	usernames := make(map[string]string)
+
+	for i, edge := range edges {
+		usernames[edge.ReviewerID] = edge.ReviewerName
+		usernames[edge.AuthorID] = edge.AuthorName
+
+		graph.Edges[i] = dto.ReviewGraphEdgeDTO{
+			ReviewerID: edge.ReviewerID,
+			AuthorID:   edge.AuthorID,
+			Reviews:    edge.Reviews,
+		}
+	}
+
+	for _, userId := range slices.Sorted(maps.Keys(usernames)) {
+		graph.Nodes = append(graph.Nodes, dto.ReviewGraphNodeDTO{
+			UserID:   userId,
+			Username: usernames[userId],
+		})
+	}
+
+	return graph, nil
+}
+
+// getActivities loads review event counts for the requested period keyed by
+// user or team id. It returns nil when no period was requested, so callers
+// keep the lifetime statistic only.
+func (s *statisticService) getActivities(
+	ctx context.Context,
+	query dto.StatisticPeriodQueryDTO,
+	filter domain.ActivityFilter,
+) (map[string]*dto.ActivityDTO, error) {
+	if query.From == nil && query.To == nil && query.GroupBy == "" {
+		return nil, nil
+	}
+
+	filter.From = query.From
+	filter.To = query.To
+	filter.GroupBy = query.GroupBy
+
+	points, err := s.statisticRepo.GetActivity(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	activities := make(map[string]*dto.ActivityDTO)
+
+	for _, point := range points {
+		activity, ok := activities[point.Key]
+		if !ok {
+			activity = &dto.ActivityDTO{}
+			activities[point.Key] = activity
+		}
+
+		activity.Assigned += point.Assigned
+		activity.Unassigned += point.Unassigned
+		activity.Merged += point.Merged
+
+		if point.PeriodStart != nil {
+			activity.Series = append(activity.Series, dto.ActivityPointDTO{
+				PeriodStart: point.PeriodStart.UTC(),
+				Assigned:    point.Assigned,
+				Unassigned:  point.Unassigned,
+				Merged:      point.Merged,
+			})
+		}
+	}
+
+	return activities, nil
+}
+
+// activityOf returns zero activity for keys without events in the period.
+func activityOf(activities map[string]*dto.ActivityDTO, key string) *dto.ActivityDTO {
+	if activities == nil {
+		return nil
+	}
+
+	if activity, ok := activities[key]; ok {
+		return activity
+	}
+
+	return &dto.ActivityDTO{}
+}
+
+func lifecycleStatisticToDTO(statistic domain.LifecycleStatistic) dto.PRLifecycleStatisticDTO {
+	lifecycle := dto.PRLifecycleStatisticDTO{
+		PRsCreated:            statistic.PRsCreated,
+		PRsMerged:             statistic.PRsMerged,
+		Reassignments:         statistic.Reassignments,
+		MaxReassignmentsPerPR: statistic.MaxReassignments,
+		UnderstaffedPRs:       statistic.UnderstaffedPRs,
+	}
+
+	if statistic.PRsCreated > 0 {
+		lifecycle.AvgReassignmentsPerPR = roundTo2(float64(statistic.Reassignments) / float64(statistic.PRsCreated))
+	}
+
+	if statistic.PRsWithStaffing > 0 {
+		share := roundTo2(float64(statistic.UnderstaffedPRs) / float64(statistic.PRsWithStaffing))
+		lifecycle.UnderstaffedShare = &share
+	}
+
+	if statistic.AvgMergeSeconds != nil {
+		lifecycle.TimeToMerge = &dto.MergeTimeDTO{
+			AvgSeconds: roundTo2(*statistic.AvgMergeSeconds),
+			P50Seconds: roundTo2(*statistic.P50MergeSeconds),
+			P90Seconds: roundTo2(*statistic.P90MergeSeconds),
+			P95Seconds: roundTo2(*statistic.P95MergeSeconds),
+		}
+	}
+
+	return lifecycle
+}
+
+// teamFairness computes the distribution metrics of the review loads. The
+// max/min ratio is left out when somebody got no reviews at all.
+func teamFairness(loads []int) dto.TeamFairnessDTO {
+	sorted := slices.Clone(loads)
+	slices.Sort(sorted)
+
+	fairness := dto.TeamFairnessDTO{
+		Members: len(sorted),
+		MinLoad: sorted[0],
+		MaxLoad: sorted[len(sorted)-1],
+	}
+
+	var weightedSum float64
+
+	for i, load := range sorted {
+		fairness.TotalReviews += load
+		weightedSum += float64(i+1) * float64(load)
+	}
+
+	n := float64(len(sorted))
+	mean := float64(fairness.TotalReviews) / n
+
+	var variance float64
+	for _, load := range sorted {
+		variance += (float64(load) - mean) * (float64(load) - mean)
+	}
+
+	fairness.MeanLoad = roundTo2(mean)
+	fairness.StdDev = roundTo2(math.Sqrt(variance / n))
+
+	if fairness.TotalReviews > 0 {
+		fairness.Gini = roundTo2(2*weightedSum/(n*float64(fairness.TotalReviews)) - (n+1)/n)
+	}
+
+	if fairness.MinLoad > 0 {
+		ratio := roundTo2(float64(fairness.MaxLoad) / float64(fairness.MinLoad))
+		fairness.MaxMinRatio = &ratio
+	}
+
+	return fairness
+}
+
+func roundTo2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func validatePeriod(query dto.StatisticPeriodQueryDTO) error {
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return appErrors.NewValidationFailedError("from must be before to")
+	}
+
+	return nil
+}
+
+func teamStatisticToDTO(statistic domain.TeamStatistic) dto.TeamStatisticDTO {
+	return dto.TeamStatisticDTO{
+		TeamName:          statistic.TeamName,
+		Archived:          statistic.ArchivedAt != nil,
+		PRsCreated:        statistic.PRsCreated,
+		PRsMerged:         statistic.PRsMerged,
+		PRsOpen:           statistic.PRsOpen,
+		AvgReviewersPerPR: statistic.AvgReviewersPerPR,
+		ActiveMembers:     statistic.ActiveMembers,
+	}
+}
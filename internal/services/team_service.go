@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"slices"
+	"time"
 
 	"github.com/L11D/avito-review-assign-service/internal/domain"
 	appErrors "github.com/L11D/avito-review-assign-service/internal/errors"
@@ -11,27 +13,74 @@ import (
 	"github.com/google/uuid"
 )
 
+const DEFAULT_TEAM_LIST_LIMIT = 20
+
 type TeamRepo interface {
 	Save(ctx context.Context, team domain.Team) (domain.Team, error)
 	GetByName(ctx context.Context, name string) (domain.Team, error)
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Team, error)
+	List(ctx context.Context, filter domain.TeamFilter) ([]domain.TeamSummary, error)
+	Count(ctx context.Context, filter domain.TeamFilter) (int, error)
+	Update(ctx context.Context, team domain.Team) (domain.Team, error)
+	HasPullRequestReferences(ctx context.Context, id uuid.UUID) (bool, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type TeamSettingsRepo interface {
+	Upsert(
+		ctx context.Context,
+		defaults domain.TeamSettings,
+		patch domain.TeamSettingsPatch,
+	) (domain.TeamSettings, error)
+	GetByTeamID(ctx context.Context, teamId uuid.UUID) (domain.TeamSettings, error)
+}
+
+type TeamSLAPolicyRepoTeamService interface {
+	ResetBackupsFromTeam(ctx context.Context, teamId uuid.UUID) error
 }
 
 type UserService interface {
 	CreateUsersInTeam(ctx context.Context, teamId uuid.UUID, members []dto.TeamMemberDTO) ([]dto.TeamMemberDTO, error)
 	GetTeamMembers(ctx context.Context, teamId uuid.UUID) ([]dto.TeamMemberDTO, error)
+	DeactivateUsers(ctx context.Context, userIds []string) ([]string, dto.ReviewsReassignmentReportDTO, error)
+	AddUsersToTeam(ctx context.Context, teamId uuid.UUID, members []dto.TeamMemberDTO) ([]dto.TeamMemberDTO, error)
+	RemoveUsersFromTeam(
+		ctx context.Context,
+		teamId uuid.UUID,
+		userIds []string,
+	) (dto.ReviewsReassignmentReportDTO, error)
+	SyncTeamUsers(
+		ctx context.Context,
+		teamId uuid.UUID,
+		members []dto.TeamMemberDTO,
+		removeAbsent bool,
+	) (dto.TeamSyncDiffDTO, error)
+	DeleteTeamUsers(ctx context.Context, teamId uuid.UUID) error
+	GetTeamRole(ctx context.Context, userId string, teamId uuid.UUID) (dto.TeamRole, error)
+	SetTeamRole(ctx context.Context, teamId uuid.UUID, userId string, role dto.TeamRole) (dto.TeamMemberDTO, error)
 }
 
 type teamService struct {
-	repo        TeamRepo
-	userService UserService
-	trManager   *manager.Manager
+	repo         TeamRepo
+	settingsRepo TeamSettingsRepo
+	policyRepo   TeamSLAPolicyRepoTeamService
+	userService  UserService
+	trManager    *manager.Manager
 }
 
-func NewTeamService(repo TeamRepo, userService UserService, trManager *manager.Manager) *teamService {
+func NewTeamService(
+	repo TeamRepo,
+	settingsRepo TeamSettingsRepo,
+	policyRepo TeamSLAPolicyRepoTeamService,
+	userService UserService,
+	trManager *manager.Manager,
+) *teamService {
 	return &teamService{
-		repo:        repo,
-		userService: userService,
-		trManager:   trManager,
+		repo:         repo,
+		settingsRepo: settingsRepo,
+		policyRepo:   policyRepo,
+		userService:  userService,
+		trManager:    trManager,
 	}
 }
 
@@ -40,6 +89,15 @@ func (s *teamService) Create(ctx context.Context, team dto.TeamDTO) (dto.TeamDTO
 		Name: team.Name,
 	}
 
+	if team.ParentTeamName != nil {
+		parent, err := s.getTeam(ctx, *team.ParentTeamName)
+		if err != nil {
+			return dto.TeamDTO{}, err
+		}
+
+		domainTeam.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
+
 	var createdTeamDTO dto.TeamDTO
 
 	err := s.trManager.Do(ctx, func(ctx context.Context) error {
@@ -58,8 +116,9 @@ func (s *teamService) Create(ctx context.Context, team dto.TeamDTO) (dto.TeamDTO
 		}
 
 		createdTeamDTO = dto.TeamDTO{
-			Name:    createdTeam.Name,
-			Members: createdMembers,
+			Name:           createdTeam.Name,
+			ParentTeamName: team.ParentTeamName,
+			Members:        createdMembers,
 		}
 
 		return nil
@@ -68,23 +127,520 @@ func (s *teamService) Create(ctx context.Context, team dto.TeamDTO) (dto.TeamDTO
 	return createdTeamDTO, err
 }
 
+// Sync creates the team when it does not exist yet and makes its roster
+// match the payload, so repeated imports of the same data change nothing.
+func (s *teamService) Sync(ctx context.Context, syncDTO dto.TeamSyncDTO) (dto.TeamSyncResultDTO, error) {
+	var result dto.TeamSyncResultDTO
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		team, err := s.repo.GetByName(ctx, syncDTO.Name)
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			team, err = s.repo.Save(ctx, domain.Team{Name: syncDTO.Name})
+			result.TeamCreated = true
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if team.ArchivedAt != nil {
+			return appErrors.NewTeamArchivedError(team.Name)
+		}
+
+		result.Changes, err = s.userService.SyncTeamUsers(ctx, team.ID, syncDTO.Members, syncDTO.RemoveAbsent)
+		if err != nil {
+			return err
+		}
+
+		members, err := s.userService.GetTeamMembers(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Team, err = s.teamToDTO(ctx, team, members)
+
+		return err
+	})
+	if err != nil {
+		return dto.TeamSyncResultDTO{}, err
+	}
+
+	return result, nil
+}
+
 func (s *teamService) GetByName(ctx context.Context, name string) (dto.TeamDTO, error) {
+	team, err := s.getTeam(ctx, name)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	members, err := s.userService.GetTeamMembers(ctx, team.ID)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	return s.teamToDTO(ctx, team, members)
+}
+
+// List returns a page of teams whose name starts with the given prefix.
+// Archived teams are hidden unless explicitly requested.
+func (s *teamService) List(ctx context.Context, listDTO dto.TeamListQueryDTO) (dto.TeamListDTO, error) {
+	filter := domain.TeamFilter{
+		NamePrefix:      listDTO.Prefix,
+		IncludeArchived: listDTO.IncludeArchived,
+		Limit:           listDTO.Limit,
+		Offset:          listDTO.Offset,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = DEFAULT_TEAM_LIST_LIMIT
+	}
+
+	teams, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return dto.TeamListDTO{}, err
+	}
+
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return dto.TeamListDTO{}, err
+	}
+
+	summaries := make([]dto.TeamSummaryDTO, len(teams))
+	for i, team := range teams {
+		summaries[i] = dto.TeamSummaryDTO{
+			Name:                 team.Name,
+			Archived:             team.ArchivedAt != nil,
+			MembersCount:         team.MembersCount,
+			ActiveMembersCount:   team.ActiveMembersCount,
+			InactiveMembersCount: team.MembersCount - team.ActiveMembersCount,
+		}
+	}
+
+	return dto.TeamListDTO{
+		Teams:  summaries,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+func (s *teamService) DeactivateUsers(
+	ctx context.Context,
+	deactivateDTO dto.TeamDeactivateUsersDTO,
+) (dto.TeamDeactivateUsersResultDTO, error) {
+	team, err := s.getTeam(ctx, deactivateDTO.TeamName)
+	if err != nil {
+		return dto.TeamDeactivateUsersResultDTO{}, err
+	}
+
+	err = s.checkLead(ctx, team, deactivateDTO.ActingUserID)
+	if err != nil {
+		return dto.TeamDeactivateUsersResultDTO{}, err
+	}
+
+	memberIds, err := s.getTeamMemberIds(ctx, team)
+	if err != nil {
+		return dto.TeamDeactivateUsersResultDTO{}, err
+	}
+
+	userIds := deactivateDTO.UserIDs
+	if len(userIds) == 0 {
+		userIds = memberIds
+	}
+
+	err = checkTeamMembership(team, memberIds, userIds)
+	if err != nil {
+		return dto.TeamDeactivateUsersResultDTO{}, err
+	}
+
+	deactivatedIds, report, err := s.userService.DeactivateUsers(ctx, userIds)
+	if err != nil {
+		return dto.TeamDeactivateUsersResultDTO{}, err
+	}
+
+	return dto.TeamDeactivateUsersResultDTO{
+		TeamName:       team.Name,
+		DeactivatedIDs: deactivatedIds,
+		Reassignment:   report,
+	}, nil
+}
+
+func (s *teamService) AddMembers(ctx context.Context, addDTO dto.TeamMembersAddDTO) (dto.TeamDTO, error) {
+	team, err := s.getTeam(ctx, addDTO.TeamName)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	err = s.checkLead(ctx, team, addDTO.ActingUserID)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	if team.ArchivedAt != nil {
+		return dto.TeamDTO{}, appErrors.NewTeamArchivedError(team.Name)
+	}
+
+	var members []dto.TeamMemberDTO
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		_, err := s.userService.AddUsersToTeam(ctx, team.ID, addDTO.Members)
+		if err != nil {
+			return err
+		}
+
+		members, err = s.userService.GetTeamMembers(ctx, team.ID)
+
+		return err
+	})
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	return s.teamToDTO(ctx, team, members)
+}
+
+func (s *teamService) RemoveMembers(
+	ctx context.Context,
+	removeDTO dto.TeamMembersRemoveDTO,
+) (dto.TeamMembersRemoveResultDTO, error) {
+	team, err := s.getTeam(ctx, removeDTO.TeamName)
+	if err != nil {
+		return dto.TeamMembersRemoveResultDTO{}, err
+	}
+
+	err = s.checkLead(ctx, team, removeDTO.ActingUserID)
+	if err != nil {
+		return dto.TeamMembersRemoveResultDTO{}, err
+	}
+
+	memberIds, err := s.getTeamMemberIds(ctx, team)
+	if err != nil {
+		return dto.TeamMembersRemoveResultDTO{}, err
+	}
+
+	err = checkTeamMembership(team, memberIds, removeDTO.UserIDs)
+	if err != nil {
+		return dto.TeamMembersRemoveResultDTO{}, err
+	}
+
+	var (
+		report  dto.ReviewsReassignmentReportDTO
+		members []dto.TeamMemberDTO
+	)
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		report, err = s.userService.RemoveUsersFromTeam(ctx, team.ID, removeDTO.UserIDs)
+		if err != nil {
+			return err
+		}
+
+		members, err = s.userService.GetTeamMembers(ctx, team.ID)
+
+		return err
+	})
+	if err != nil {
+		return dto.TeamMembersRemoveResultDTO{}, err
+	}
+
+	teamDTO, err := s.teamToDTO(ctx, team, members)
+	if err != nil {
+		return dto.TeamMembersRemoveResultDTO{}, err
+	}
+
+	return dto.TeamMembersRemoveResultDTO{
+		Team:         teamDTO,
+		Reassignment: report,
+	}, nil
+}
+
+func (s *teamService) SetMemberRole(ctx context.Context, roleDTO dto.TeamSetMemberRoleDTO) (dto.TeamMemberDTO, error) {
+	team, err := s.getTeam(ctx, roleDTO.TeamName)
+	if err != nil {
+		return dto.TeamMemberDTO{}, err
+	}
+
+	err = s.checkLead(ctx, team, &roleDTO.ActingUserID)
+	if err != nil {
+		return dto.TeamMemberDTO{}, err
+	}
+
+	return s.userService.SetTeamRole(ctx, team.ID, roleDTO.UserID, roleDTO.Role)
+}
+
+func (s *teamService) Rename(ctx context.Context, renameDTO dto.TeamRenameDTO) (dto.TeamDTO, error) {
+	team, err := s.getTeam(ctx, renameDTO.TeamName)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	team.Name = renameDTO.NewTeamName
+
+	renamedTeam, err := s.repo.Update(ctx, team)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrAlreadyExists) {
+			return dto.TeamDTO{}, appErrors.NewTeamExistsError(renameDTO.NewTeamName)
+		}
+
+		return dto.TeamDTO{}, err
+	}
+
+	members, err := s.userService.GetTeamMembers(ctx, renamedTeam.ID)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	return s.teamToDTO(ctx, renamedTeam, members)
+}
+
+// SetParent attaches the team to a parent team, or detaches it when no
+// parent is given. Links that would make the hierarchy cyclic are rejected.
+func (s *teamService) SetParent(ctx context.Context, setParentDTO dto.TeamSetParentDTO) (dto.TeamDTO, error) {
+	team, err := s.getTeam(ctx, setParentDTO.TeamName)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	team.ParentID = uuid.NullUUID{}
+
+	if setParentDTO.ParentTeamName != nil {
+		parent, err := s.getTeam(ctx, *setParentDTO.ParentTeamName)
+		if err != nil {
+			return dto.TeamDTO{}, err
+		}
+
+		for ancestor := parent; ; {
+			if ancestor.ID == team.ID {
+				return dto.TeamDTO{}, appErrors.NewValidationFailedError(
+					"Team '" + parent.Name + "' cannot be a parent of its ancestor '" + team.Name + "'",
+				)
+			}
+
+			if !ancestor.ParentID.Valid {
+				break
+			}
+
+			ancestor, err = s.repo.GetByID(ctx, ancestor.ParentID.UUID)
+			if err != nil {
+				return dto.TeamDTO{}, err
+			}
+		}
+
+		team.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
+
+	updatedTeam, err := s.repo.Update(ctx, team)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	members, err := s.userService.GetTeamMembers(ctx, updatedTeam.ID)
+	if err != nil {
+		return dto.TeamDTO{}, err
+	}
+
+	return s.teamToDTO(ctx, updatedTeam, members)
+}
+
+// SetArchived archives or restores the team. Archived teams keep their
+// members and PR history, but their members cannot open new PRs.
+func (s *teamService) SetArchived(ctx context.Context, teamName string, archived bool) (dto.TeamArchiveStatusDTO, error) {
+	team, err := s.getTeam(ctx, teamName)
+	if err != nil {
+		return dto.TeamArchiveStatusDTO{}, err
+	}
+
+	if (team.ArchivedAt != nil) != archived {
+		team.ArchivedAt = nil
+
+		if archived {
+			now := time.Now().UTC()
+			team.ArchivedAt = &now
+		}
+
+		team, err = s.repo.Update(ctx, team)
+		if err != nil {
+			return dto.TeamArchiveStatusDTO{}, err
+		}
+	}
+
+	return dto.TeamArchiveStatusDTO{
+		TeamName:   team.Name,
+		Archived:   team.ArchivedAt != nil,
+		ArchivedAt: team.ArchivedAt,
+	}, nil
+}
+
+// Delete removes the team together with its members. It is only allowed
+// while none of the members is referenced by a pull request. SLA policies of
+// other teams that escalate to one of the members fall back to reassignment.
+func (s *teamService) Delete(ctx context.Context, teamName string) error {
+	team, err := s.getTeam(ctx, teamName)
+	if err != nil {
+		return err
+	}
+
+	return s.trManager.Do(ctx, func(ctx context.Context) error {
+		referenced, err := s.repo.HasPullRequestReferences(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+
+		if referenced {
+			return appErrors.NewTeamInUseError(team.Name)
+		}
+
+		err = s.policyRepo.ResetBackupsFromTeam(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.userService.DeleteTeamUsers(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+
+		return s.repo.Delete(ctx, team.ID)
+	})
+}
+
+func (s *teamService) GetSettings(ctx context.Context, teamName string) (dto.TeamSettingsDTO, error) {
+	team, err := s.getTeam(ctx, teamName)
+	if err != nil {
+		return dto.TeamSettingsDTO{}, err
+	}
+
+	settings, err := s.getSettings(ctx, team.ID)
+	if err != nil {
+		return dto.TeamSettingsDTO{}, err
+	}
+
+	return settingsToDTO(team.Name, settings), nil
+}
+
+// UpdateSettings changes the fields present in the request and keeps the rest.
+// The reviewer service reads the settings on every assignment, so the new
+// values apply to the next pull request.
+func (s *teamService) UpdateSettings(
+	ctx context.Context,
+	updateDTO dto.TeamSettingsUpdateDTO,
+) (dto.TeamSettingsDTO, error) {
+	team, err := s.getTeam(ctx, updateDTO.TeamName)
+	if err != nil {
+		return dto.TeamSettingsDTO{}, err
+	}
+
+	settings, err := s.settingsRepo.Upsert(ctx, defaultTeamSettings(team.ID), domain.TeamSettingsPatch{
+		TeamID:         team.ID,
+		ReviewersCount: updateDTO.ReviewersCount,
+		Strategy:       updateDTO.Strategy,
+		OwnTeamRule:    updateDTO.OwnTeamRule,
+	})
+	if err != nil {
+		return dto.TeamSettingsDTO{}, err
+	}
+
+	return settingsToDTO(team.Name, settings), nil
+}
+
+func (s *teamService) getSettings(ctx context.Context, teamId uuid.UUID) (domain.TeamSettings, error) {
+	settings, err := s.settingsRepo.GetByTeamID(ctx, teamId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return defaultTeamSettings(teamId), nil
+		}
+
+		return domain.TeamSettings{}, err
+	}
+
+	return settings, nil
+}
+
+func settingsToDTO(teamName string, settings domain.TeamSettings) dto.TeamSettingsDTO {
+	return dto.TeamSettingsDTO{
+		TeamName:       teamName,
+		ReviewersCount: settings.ReviewersCount,
+		Strategy:       settings.Strategy,
+		OwnTeamRule:    settings.OwnTeamRule,
+		UpdatedAt:      settings.UpdatedAt,
+	}
+}
+
+func (s *teamService) getTeam(ctx context.Context, name string) (domain.Team, error) {
 	team, err := s.repo.GetByName(ctx, name)
 	if err != nil {
 		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
-			return dto.TeamDTO{}, appErrors.NewNotFoundError("Team with name '" + name + "'")
+			return domain.Team{}, appErrors.NewNotFoundError("Team with name '" + name + "'")
 		}
 
-		return dto.TeamDTO{}, err
+		return domain.Team{}, err
+	}
+
+	return team, nil
+}
+
+// checkLead allows roster changes on behalf of a user only when that user
+// leads the team. Roster endpoints called without an acting user are
+// administrative and trusted as before; role changes always pass one.
+func (s *teamService) checkLead(ctx context.Context, team domain.Team, actingUserId *string) error {
+	if actingUserId == nil {
+		return nil
 	}
 
+	role, err := s.userService.GetTeamRole(ctx, *actingUserId, team.ID)
+	if err != nil {
+		return err
+	}
+
+	if role != dto.TeamRoleLead {
+		return appErrors.NewForbiddenError("User '" + *actingUserId + "' is not a lead of team '" + team.Name + "'")
+	}
+
+	return nil
+}
+
+func (s *teamService) getTeamMemberIds(ctx context.Context, team domain.Team) ([]string, error) {
 	members, err := s.userService.GetTeamMembers(ctx, team.ID)
 	if err != nil {
-		return dto.TeamDTO{}, err
+		return nil, err
 	}
 
-	return dto.TeamDTO{
+	memberIds := make([]string, len(members))
+	for i, member := range members {
+		memberIds[i] = member.ID
+	}
+
+	return memberIds, nil
+}
+
+func (s *teamService) teamToDTO(
+	ctx context.Context,
+	team domain.Team,
+	members []dto.TeamMemberDTO,
+) (dto.TeamDTO, error) {
+	teamDTO := dto.TeamDTO{
 		Name:    team.Name,
 		Members: members,
-	}, nil
+	}
+
+	if team.ParentID.Valid {
+		parent, err := s.repo.GetByID(ctx, team.ParentID.UUID)
+		if err != nil {
+			return dto.TeamDTO{}, err
+		}
+
+		teamDTO.ParentTeamName = &parent.Name
+	}
+
+	return teamDTO, nil
+}
+
+func checkTeamMembership(team domain.Team, memberIds []string, userIds []string) error {
+	for _, userId := range userIds {
+		if !slices.Contains(memberIds, userId) {
+			return appErrors.NewNotFoundError("User with ID '" + userId + "' in team '" + team.Name + "'")
+		}
+	}
+
+	return nil
 }
@@ -2,7 +2,12 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/L11D/avito-review-assign-service/internal/domain"
 	appErrors "github.com/L11D/avito-review-assign-service/internal/errors"
@@ -11,39 +16,97 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	ANONYMIZED_USERNAME        = "Deleted user"
+	DEFAULT_USER_PR_LIST_LIMIT = 50
+)
+
 type UserRepo interface {
 	Save(ctx context.Context, user domain.User) (domain.User, error)
 	GetByTeamID(ctx context.Context, teamId uuid.UUID) ([]domain.User, error)
 	Update(ctx context.Context, user domain.User) (domain.User, error)
+	UpdateProfile(ctx context.Context, user domain.User) (domain.User, error)
+	Anonymize(ctx context.Context, userId string, username string) (domain.User, error)
 	GetByID(ctx context.Context, userId string) (domain.User, error)
+	GetByIDs(ctx context.Context, userIds []string) ([]domain.User, error)
+	SetIsActiveByIDs(ctx context.Context, userIds []string, isActive bool) ([]domain.User, error)
+	SetTeamByIDs(ctx context.Context, userIds []string, teamId uuid.NullUUID) ([]domain.User, error)
+	DeleteByTeamID(ctx context.Context, teamId uuid.UUID) error
 }
 
 type PullRequestRepoUserService interface {
-	GetByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error)
+	GetByUserId(ctx context.Context, userId string, filter domain.PullRequestFilter) ([]domain.PullRequest, error)
+	GetByAuthorId(ctx context.Context, authorId string, filter domain.PullRequestFilter) ([]domain.PullRequest, error)
+	GetReviewQueue(ctx context.Context, userId string, includeReviewed bool) ([]domain.ReviewQueueItem, error)
+}
+
+type PullRequestReviewerRepoUserService interface {
+	GetByPRIds(ctx context.Context, prIds []string) ([]domain.PullRequestReviewer, error)
 }
 
 type TeamRepoUserService interface {
 	GetByID(ctx context.Context, id uuid.UUID) (domain.Team, error)
+	GetByName(ctx context.Context, name string) (domain.Team, error)
+}
+
+type TeamMembershipHistoryRepo interface {
+	SaveBatch(ctx context.Context, changes []domain.TeamMembershipChange) error
+	GetByUserID(ctx context.Context, userId string) ([]domain.TeamMembershipChange, error)
+}
+
+type TeamMembershipRepo interface {
+	Save(ctx context.Context, membership domain.TeamMembership) (domain.TeamMembership, error)
+	GetByUserID(ctx context.Context, userId string) ([]domain.TeamMembership, error)
+	Update(ctx context.Context, membership domain.TeamMembership) (domain.TeamMembership, error)
+	Delete(ctx context.Context, userId string, teamId uuid.UUID) error
+	DeleteByUserID(ctx context.Context, userId string) error
+}
+
+type UserIdentityRepo interface {
+	Save(ctx context.Context, identity domain.UserIdentity) (domain.UserIdentity, error)
+	GetByExternalID(ctx context.Context, provider dto.IdentityProvider, externalId string) (domain.UserIdentity, error)
+	GetByUserID(ctx context.Context, userId string) ([]domain.UserIdentity, error)
+	Delete(ctx context.Context, identity domain.UserIdentity) error
+	DeleteByUserID(ctx context.Context, userId string) error
+}
+
+type ReviewerServiceUserService interface {
+	ReassignUsersReviews(ctx context.Context, userIds []string) (dto.ReviewsReassignmentReportDTO, error)
 }
 
 type userService struct {
-	userRepo  UserRepo
-	teamRepo  TeamRepoUserService
-	prRepo    PullRequestRepoUserService
-	trManager *manager.Manager
+	userRepo        UserRepo
+	teamRepo        TeamRepoUserService
+	prRepo          PullRequestRepoUserService
+	prReviewerRepo  PullRequestReviewerRepoUserService
+	historyRepo     TeamMembershipHistoryRepo
+	membershipRepo  TeamMembershipRepo
+	identityRepo    UserIdentityRepo
+	reviewerService ReviewerServiceUserService
+	trManager       *manager.Manager
 }
 
 func NewUserService(
 	userRepo UserRepo,
 	teamRepo TeamRepoUserService,
 	prRepo PullRequestRepoUserService,
+	prReviewerRepo PullRequestReviewerRepoUserService,
+	historyRepo TeamMembershipHistoryRepo,
+	membershipRepo TeamMembershipRepo,
+	identityRepo UserIdentityRepo,
+	reviewerService ReviewerServiceUserService,
 	trManager *manager.Manager,
 ) *userService {
 	return &userService{
-		userRepo:  userRepo,
-		teamRepo:  teamRepo,
-		prRepo:    prRepo,
-		trManager: trManager,
+		userRepo:        userRepo,
+		teamRepo:        teamRepo,
+		prRepo:          prRepo,
+		prReviewerRepo:  prReviewerRepo,
+		historyRepo:     historyRepo,
+		membershipRepo:  membershipRepo,
+		identityRepo:    identityRepo,
+		reviewerService: reviewerService,
+		trManager:       trManager,
 	}
 }
 
@@ -75,6 +138,240 @@ func (s *userService) CreateUsersInTeam(
 	return createdMembers, err
 }
 
+// AddUsersToTeam creates the missing users and attaches users that were
+// removed from their previous team. Users that already belong to a team are
+// rejected with USER_EXISTS.
+func (s *userService) AddUsersToTeam(
+	ctx context.Context,
+	teamId uuid.UUID,
+	members []dto.TeamMemberDTO,
+) ([]dto.TeamMemberDTO, error) {
+	addedMembers := make([]dto.TeamMemberDTO, len(members))
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		var changes []domain.TeamMembershipChange
+
+		for i, member := range members {
+			user, err := s.userRepo.GetByID(ctx, member.ID)
+			if err != nil && !errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+				return err
+			}
+
+			if err == nil && user.TeamID.Valid {
+				return appErrors.NewUserExistsError(member.ID)
+			}
+
+			if err == nil && user.AnonymizedAt != nil {
+				return appErrors.NewUserAnonymizedError(member.ID)
+			}
+
+			newUser := memberDTOtoUser(member, teamId)
+			if err == nil {
+				err = s.membershipRepo.Delete(ctx, member.ID, teamId)
+				if err != nil {
+					return err
+				}
+
+				newUser.AssignRate = user.AssignRate
+				user, err = s.userRepo.Update(ctx, newUser)
+
+				changes = append(changes, newMembershipChange(member.ID, uuid.NullUUID{}, newUser.TeamID))
+			} else {
+				user, err = s.userRepo.Save(ctx, newUser)
+			}
+
+			if err != nil {
+				if errors.Is(appErrors.MapPgError(err), appErrors.ErrAlreadyExists) {
+					return appErrors.NewUserExistsError(member.ID)
+				}
+
+				return err
+			}
+
+			addedMembers[i] = userToMemberDTO(user)
+		}
+
+		return s.historyRepo.SaveBatch(ctx, changes)
+	})
+
+	return addedMembers, err
+}
+
+// RemoveUsersFromTeam detaches the users from their team and moves their
+// open reviews to the remaining teammates of the PR authors.
+func (s *userService) RemoveUsersFromTeam(
+	ctx context.Context,
+	teamId uuid.UUID,
+	userIds []string,
+) (dto.ReviewsReassignmentReportDTO, error) {
+	var report dto.ReviewsReassignmentReportDTO
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		_, err := s.userRepo.SetTeamByIDs(ctx, userIds, uuid.NullUUID{})
+		if err != nil {
+			return err
+		}
+
+		changes := make([]domain.TeamMembershipChange, len(userIds))
+		for i, userId := range userIds {
+			changes[i] = newMembershipChange(userId, uuid.NullUUID{UUID: teamId, Valid: true}, uuid.NullUUID{})
+		}
+
+		err = s.historyRepo.SaveBatch(ctx, changes)
+		if err != nil {
+			return err
+		}
+
+		report, err = s.reviewerService.ReassignUsersReviews(ctx, userIds)
+
+		return err
+	})
+	if err != nil {
+		return dto.ReviewsReassignmentReportDTO{}, err
+	}
+
+	return report, nil
+}
+
+// SyncTeamUsers makes the team roster match members: missing users are
+// created, users from other teams are moved in and usernames and activity
+// flags are updated. Roles change only when the member carries one. With
+// removeAbsent set, members missing from the list are
+// detached from the team. Open reviews of deactivated and removed users are
+// reassigned; moved users keep theirs.
+func (s *userService) SyncTeamUsers(
+	ctx context.Context,
+	teamId uuid.UUID,
+	members []dto.TeamMemberDTO,
+	removeAbsent bool,
+) (dto.TeamSyncDiffDTO, error) {
+	diff := dto.TeamSyncDiffDTO{
+		Added:   []string{},
+		Moved:   []string{},
+		Updated: []string{},
+		Removed: []string{},
+	}
+
+	memberIds := make([]string, len(members))
+	for i, member := range members {
+		if slices.Contains(memberIds[:i], member.ID) {
+			return dto.TeamSyncDiffDTO{}, appErrors.NewValidationFailedError(
+				"User with ID '" + member.ID + "' is listed more than once",
+			)
+		}
+
+		memberIds[i] = member.ID
+	}
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		existingUsers, err := s.userRepo.GetByIDs(ctx, memberIds)
+		if err != nil {
+			return err
+		}
+
+		usersById := make(map[string]domain.User, len(existingUsers))
+		for _, user := range existingUsers {
+			usersById[user.ID] = user
+		}
+
+		var (
+			changes        []domain.TeamMembershipChange
+			reassignIds    []string
+			teamMembership = uuid.NullUUID{UUID: teamId, Valid: true}
+		)
+
+		for _, member := range members {
+			user, exists := usersById[member.ID]
+			if exists && user.AnonymizedAt != nil {
+				return appErrors.NewUserAnonymizedError(member.ID)
+			}
+
+			newUser := memberDTOtoUser(member, teamId)
+
+			// HR payloads usually carry no roles, so an omitted role keeps the
+			// one the user already has in this team.
+			if exists && member.Role == "" && user.TeamID == teamMembership {
+				newUser.Role = user.Role
+			}
+
+			switch {
+			case !exists:
+				_, err = s.userRepo.Save(ctx, newUser)
+				diff.Added = append(diff.Added, member.ID)
+			case user.TeamID != teamMembership:
+				err = s.membershipRepo.Delete(ctx, member.ID, teamId)
+				if err != nil {
+					return err
+				}
+
+				newUser.AssignRate = user.AssignRate
+				_, err = s.userRepo.Update(ctx, newUser)
+				diff.Moved = append(diff.Moved, member.ID)
+				changes = append(changes, newMembershipChange(member.ID, user.TeamID, teamMembership))
+			case user.Username != newUser.Username || user.IsActive != newUser.IsActive || user.Role != newUser.Role:
+				newUser.AssignRate = user.AssignRate
+				_, err = s.userRepo.Update(ctx, newUser)
+				diff.Updated = append(diff.Updated, member.ID)
+			default:
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if exists && user.IsActive && !newUser.IsActive {
+				reassignIds = append(reassignIds, member.ID)
+			}
+		}
+
+		if removeAbsent {
+			teamUsers, err := s.userRepo.GetByTeamID(ctx, teamId)
+			if err != nil {
+				return err
+			}
+
+			for _, user := range teamUsers {
+				if !slices.Contains(memberIds, user.ID) {
+					diff.Removed = append(diff.Removed, user.ID)
+					changes = append(changes, newMembershipChange(user.ID, teamMembership, uuid.NullUUID{}))
+				}
+			}
+
+			if len(diff.Removed) > 0 {
+				_, err = s.userRepo.SetTeamByIDs(ctx, diff.Removed, uuid.NullUUID{})
+				if err != nil {
+					return err
+				}
+
+				reassignIds = append(reassignIds, diff.Removed...)
+			}
+		}
+
+		err = s.historyRepo.SaveBatch(ctx, changes)
+		if err != nil || len(reassignIds) == 0 {
+			return err
+		}
+
+		report, err := s.reviewerService.ReassignUsersReviews(ctx, reassignIds)
+		if err != nil {
+			return err
+		}
+
+		diff.Reassignment = &report
+
+		return nil
+	})
+	if err != nil {
+		return dto.TeamSyncDiffDTO{}, err
+	}
+
+	return diff, nil
+}
+
+func (s *userService) DeleteTeamUsers(ctx context.Context, teamId uuid.UUID) error {
+	return s.userRepo.DeleteByTeamID(ctx, teamId)
+}
+
 func (s *userService) GetTeamMembers(ctx context.Context, teamId uuid.UUID) ([]dto.TeamMemberDTO, error) {
 	users, err := s.userRepo.GetByTeamID(ctx, teamId)
 	if err != nil {
@@ -89,102 +386,1005 @@ func (s *userService) GetTeamMembers(ctx context.Context, teamId uuid.UUID) ([]d
 	return memberDTOs, nil
 }
 
-func (s *userService) SetIsActive(ctx context.Context, userSetIsActiveDTO dto.UserSetIsActiveDTO) (dto.UserDTO, error) {
+func (s *userService) SetIsActive(
+	ctx context.Context,
+	userSetIsActiveDTO dto.UserSetIsActiveDTO,
+) (dto.UserSetIsActiveResultDTO, error) {
 	user, err := s.userRepo.GetByID(ctx, userSetIsActiveDTO.UserID)
 	if err != nil {
 		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
-			return dto.UserDTO{}, appErrors.NewNotFoundError("User with ID '" + userSetIsActiveDTO.UserID + "'")
+			return dto.UserSetIsActiveResultDTO{},
+				appErrors.NewNotFoundError("User with ID '" + userSetIsActiveDTO.UserID + "'")
 		}
 
-		return dto.UserDTO{}, err
+		return dto.UserSetIsActiveResultDTO{}, err
+	}
+
+	if user.AnonymizedAt != nil && *userSetIsActiveDTO.IsActive {
+		return dto.UserSetIsActiveResultDTO{}, appErrors.NewUserAnonymizedError(user.ID)
 	}
 
+	var reassignment *dto.ReviewsReassignmentReportDTO
+
 	if user.IsActive != *userSetIsActiveDTO.IsActive {
 		user.IsActive = *userSetIsActiveDTO.IsActive
 
-		user, err = s.userRepo.Update(ctx, user)
+		err = s.trManager.Do(ctx, func(ctx context.Context) error {
+			user, err = s.userRepo.Update(ctx, user)
+			if err != nil {
+				return err
+			}
+
+			if user.IsActive {
+				return nil
+			}
+
+			report, err := s.reviewerService.ReassignUsersReviews(ctx, []string{user.ID})
+			if err != nil {
+				return err
+			}
+
+			reassignment = &report
+
+			return nil
+		})
 		if err != nil {
-			return dto.UserDTO{}, err
+			return dto.UserSetIsActiveResultDTO{}, err
 		}
 	}
 
-	team, err := s.teamRepo.GetByID(ctx, user.TeamID)
+	teamName, err := s.getTeamName(ctx, user)
 	if err != nil {
-		return dto.UserDTO{}, err
+		return dto.UserSetIsActiveResultDTO{}, err
 	}
 
-	return dto.UserDTO{
-		ID:       user.ID,
-		Username: user.Username,
-		IsActive: user.IsActive,
-		TeamName: team.Name,
-	}, err
+	return dto.UserSetIsActiveResultDTO{
+		UserDTO:      userToDTO(user, teamName),
+		Reassignment: reassignment,
+	}, nil
 }
 
-func (s *userService) GetReviews(ctx context.Context, userId string) (dto.UserPRsDTO, error) {
-	user, err := s.userRepo.GetByID(ctx, userId)
+// DeactivateUsers deactivates the users and moves their open reviews to the
+// remaining active teammates in a single transaction.
+func (s *userService) DeactivateUsers(
+	ctx context.Context,
+	userIds []string,
+) ([]string, dto.ReviewsReassignmentReportDTO, error) {
+	var (
+		deactivatedIds []string
+		report         dto.ReviewsReassignmentReportDTO
+	)
+
+	err := s.trManager.Do(ctx, func(ctx context.Context) error {
+		updatedUsers, err := s.userRepo.SetIsActiveByIDs(ctx, userIds, false)
+		if err != nil {
+			return err
+		}
+
+		deactivatedIds = make([]string, len(updatedUsers))
+		for i, user := range updatedUsers {
+			deactivatedIds[i] = user.ID
+		}
+
+		for _, userId := range userIds {
+			if !slices.Contains(deactivatedIds, userId) {
+				return appErrors.NewNotFoundError("User with ID '" + userId + "'")
+			}
+		}
+
+		report, err = s.reviewerService.ReassignUsersReviews(ctx, deactivatedIds)
+
+		return err
+	})
+	if err != nil {
+		return nil, dto.ReviewsReassignmentReportDTO{}, err
+	}
+
+	return deactivatedIds, report, nil
+}
+
+// MoveTeam transfers the user to another team, replacing an additional
+// membership in it if there was one. Open reviews stay with the user unless
+// ReassignReviews is set, in which case they are handed over to other members
+// of the PR authors' teams.
+func (s *userService) MoveTeam(ctx context.Context, moveDTO dto.UserMoveTeamDTO) (dto.UserMoveTeamResultDTO, error) {
+	user, err := s.userRepo.GetByID(ctx, moveDTO.UserID)
 	if err != nil {
 		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
-			return dto.UserPRsDTO{}, appErrors.NewNotFoundError("User with ID '" + userId + "'")
+			return dto.UserMoveTeamResultDTO{}, appErrors.NewNotFoundError("User with ID '" + moveDTO.UserID + "'")
 		}
 
-		return dto.UserPRsDTO{}, err
+		return dto.UserMoveTeamResultDTO{}, err
+	}
+
+	if user.AnonymizedAt != nil {
+		return dto.UserMoveTeamResultDTO{}, appErrors.NewUserAnonymizedError(user.ID)
 	}
 
-	prs, err := s.prRepo.GetByUserId(ctx, userId)
+	newTeam, err := s.teamRepo.GetByName(ctx, moveDTO.TeamName)
 	if err != nil {
-		return dto.UserPRsDTO{}, err
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.UserMoveTeamResultDTO{}, appErrors.NewNotFoundError("Team with name '" + moveDTO.TeamName + "'")
+		}
+
+		return dto.UserMoveTeamResultDTO{}, err
 	}
 
-	prDTOs := make([]dto.PullRequestShortDTO, len(prs))
-	for i, pr := range prs {
-		prDTOs[i] = dto.PullRequestShortDTO{
-			ID:       pr.ID,
-			Name:     pr.Name,
-			Status:   pr.Status,
-			AuthorID: pr.AuthorID,
+	if newTeam.ArchivedAt != nil {
+		return dto.UserMoveTeamResultDTO{}, appErrors.NewTeamArchivedError(newTeam.Name)
+	}
+
+	if user.TeamID.Valid && user.TeamID.UUID == newTeam.ID {
+		return dto.UserMoveTeamResultDTO{}, appErrors.NewValidationFailedError(
+			"User '" + user.ID + "' is already a member of team '" + newTeam.Name + "'",
+		)
+	}
+
+	previousTeamName, err := s.getTeamName(ctx, user)
+	if err != nil {
+		return dto.UserMoveTeamResultDTO{}, err
+	}
+
+	change := newMembershipChange(user.ID, user.TeamID, uuid.NullUUID{UUID: newTeam.ID, Valid: true})
+
+	var reassignment *dto.ReviewsReassignmentReportDTO
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		user.TeamID = change.ToTeamID
+		user.Role = dto.TeamRoleMember
+
+		user, err = s.userRepo.Update(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		err = s.membershipRepo.Delete(ctx, user.ID, newTeam.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.historyRepo.SaveBatch(ctx, []domain.TeamMembershipChange{change})
+		if err != nil {
+			return err
 		}
+
+		if !moveDTO.ReassignReviews {
+			return nil
+		}
+
+		report, err := s.reviewerService.ReassignUsersReviews(ctx, []string{user.ID})
+		if err != nil {
+			return err
+		}
+
+		reassignment = &report
+
+		return nil
+	})
+	if err != nil {
+		return dto.UserMoveTeamResultDTO{}, err
 	}
 
-	return dto.UserPRsDTO{
-		UserID:       user.ID,
-		PullRequests: prDTOs,
+	return dto.UserMoveTeamResultDTO{
+		UserDTO:          userToDTO(user, newTeam.Name),
+		PreviousTeamName: previousTeamName,
+		MovedAt:          change.ChangedAt,
+		Reassignment:     reassignment,
 	}, nil
 }
 
-func (s *userService) IncrementAssignRate(ctx context.Context, userId string) (domain.User, error) {
-	user, err := s.userRepo.GetByID(ctx, userId)
+func (s *userService) GetTeamHistory(ctx context.Context, userId string) (dto.UserTeamHistoryDTO, error) {
+	_, err := s.userRepo.GetByID(ctx, userId)
 	if err != nil {
 		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
-			return domain.User{}, appErrors.NewNotFoundError("User with ID '" + userId + "'")
+			return dto.UserTeamHistoryDTO{}, appErrors.NewNotFoundError("User with ID '" + userId + "'")
 		}
 
-		return domain.User{}, err
+		return dto.UserTeamHistoryDTO{}, err
 	}
 
-	user.AssignRate += 1
-
-	updatedUser, err := s.userRepo.Update(ctx, user)
+	changes, err := s.historyRepo.GetByUserID(ctx, userId)
 	if err != nil {
-		return domain.User{}, err
+		return dto.UserTeamHistoryDTO{}, err
 	}
 
-	return updatedUser, nil
+	teamNames := make(map[uuid.UUID]*string)
+	getName := func(teamId uuid.NullUUID) (*string, error) {
+		if !teamId.Valid {
+			return nil, nil
+		}
+
+		if name, ok := teamNames[teamId.UUID]; ok {
+			return name, nil
+		}
+
+		team, err := s.teamRepo.GetByID(ctx, teamId.UUID)
+		if err != nil {
+			return nil, err
+		}
+
+		teamNames[teamId.UUID] = &team.Name
+
+		return &team.Name, nil
+	}
+
+	changeDTOs := make([]dto.TeamMembershipChangeDTO, len(changes))
+	for i, change := range changes {
+		fromTeamName, err := getName(change.FromTeamID)
+		if err != nil {
+			return dto.UserTeamHistoryDTO{}, err
+		}
+
+		toTeamName, err := getName(change.ToTeamID)
+		if err != nil {
+			return dto.UserTeamHistoryDTO{}, err
+		}
+
+		changeDTOs[i] = dto.TeamMembershipChangeDTO{
+			FromTeamName: fromTeamName,
+			ToTeamName:   toTeamName,
+			ChangedAt:    change.ChangedAt,
+		}
+	}
+
+	return dto.UserTeamHistoryDTO{
+		UserID:  userId,
+		Changes: changeDTOs,
+	}, nil
 }
 
-func memberDTOtoUser(dto dto.TeamMemberDTO, teamId uuid.UUID) domain.User {
-	return domain.User{
-		ID:       dto.ID,
-		Username: dto.Username,
-		IsActive: *dto.IsActive,
-		TeamID:   teamId,
+// JoinTeam adds the user to a team in addition to their primary one, so they
+// can review PRs created in that team.
+func (s *userService) JoinTeam(ctx context.Context, membershipDTO dto.UserTeamMembershipDTO) (dto.UserTeamsDTO, error) {
+	user, team, err := s.getUserAndTeam(ctx, membershipDTO)
+	if err != nil {
+		return dto.UserTeamsDTO{}, err
+	}
+
+	if user.AnonymizedAt != nil {
+		return dto.UserTeamsDTO{}, appErrors.NewUserAnonymizedError(user.ID)
+	}
+
+	if team.ArchivedAt != nil {
+		return dto.UserTeamsDTO{}, appErrors.NewTeamArchivedError(team.Name)
 	}
+
+	if user.TeamID.Valid && user.TeamID.UUID == team.ID {
+		return dto.UserTeamsDTO{}, appErrors.NewValidationFailedError(
+			"Team '" + team.Name + "' is the primary team of user '" + user.ID + "'",
+		)
+	}
+
+	_, err = s.membershipRepo.Save(ctx, domain.TeamMembership{
+		UserID: user.ID,
+		TeamID: team.ID,
+		Role:   roleOrDefault(membershipDTO.Role),
+	})
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrAlreadyExists) {
+			return dto.UserTeamsDTO{}, appErrors.NewValidationFailedError(
+				"User '" + user.ID + "' is already a member of team '" + team.Name + "'",
+			)
+		}
+
+		return dto.UserTeamsDTO{}, err
+	}
+
+	return s.getUserTeams(ctx, user)
 }
 
-func userToMemberDTO(user domain.User) dto.TeamMemberDTO {
-	return dto.TeamMemberDTO{
-		ID:       user.ID,
-		Username: user.Username,
-		IsActive: &user.IsActive,
+// LeaveTeam removes an additional membership. The primary team can only be
+// changed through MoveTeam or the team roster endpoints.
+func (s *userService) LeaveTeam(ctx context.Context, membershipDTO dto.UserTeamMembershipDTO) (dto.UserTeamsDTO, error) {
+	user, team, err := s.getUserAndTeam(ctx, membershipDTO)
+	if err != nil {
+		return dto.UserTeamsDTO{}, err
 	}
+
+	memberships, err := s.membershipRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return dto.UserTeamsDTO{}, err
+	}
+
+	isMember := slices.ContainsFunc(memberships, func(membership domain.TeamMembership) bool {
+		return membership.TeamID == team.ID
+	})
+	if !isMember {
+		return dto.UserTeamsDTO{}, appErrors.NewNotFoundError(
+			"Additional membership of user '" + user.ID + "' in team '" + team.Name + "'",
+		)
+	}
+
+	err = s.membershipRepo.Delete(ctx, user.ID, team.ID)
+	if err != nil {
+		return dto.UserTeamsDTO{}, err
+	}
+
+	return s.getUserTeams(ctx, user)
+}
+
+// GetTeamRole returns the role of the user in the team through either the
+// primary team or an additional membership, or an empty role for outsiders.
+func (s *userService) GetTeamRole(ctx context.Context, userId string, teamId uuid.UUID) (dto.TeamRole, error) {
+	user, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return "", appErrors.NewNotFoundError("User with ID '" + userId + "'")
+		}
+
+		return "", err
+	}
+
+	if user.TeamID.Valid && user.TeamID.UUID == teamId {
+		return user.Role, nil
+	}
+
+	memberships, err := s.membershipRepo.GetByUserID(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+
+	for _, membership := range memberships {
+		if membership.TeamID == teamId {
+			return membership.Role, nil
+		}
+	}
+
+	return "", nil
+}
+
+// SetTeamRole changes the role of a primary or additional team member.
+func (s *userService) SetTeamRole(
+	ctx context.Context,
+	teamId uuid.UUID,
+	userId string,
+	role dto.TeamRole,
+) (dto.TeamMemberDTO, error) {
+	user, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.TeamMemberDTO{}, appErrors.NewNotFoundError("User with ID '" + userId + "'")
+		}
+
+		return dto.TeamMemberDTO{}, err
+	}
+
+	if user.TeamID.Valid && user.TeamID.UUID == teamId {
+		user.Role = role
+
+		user, err = s.userRepo.Update(ctx, user)
+		if err != nil {
+			return dto.TeamMemberDTO{}, err
+		}
+
+		return userToMemberDTO(user), nil
+	}
+
+	membership, err := s.membershipRepo.Update(ctx, domain.TeamMembership{
+		UserID: userId,
+		TeamID: teamId,
+		Role:   role,
+	})
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.TeamMemberDTO{}, appErrors.NewNotFoundError("Membership of user '" + userId + "' in the team")
+		}
+
+		return dto.TeamMemberDTO{}, err
+	}
+
+	user.Role = membership.Role
+
+	return userToMemberDTO(user), nil
+}
+
+func (s *userService) GetTeams(ctx context.Context, userId string) (dto.UserTeamsDTO, error) {
+	user, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.UserTeamsDTO{}, appErrors.NewNotFoundError("User with ID '" + userId + "'")
+		}
+
+		return dto.UserTeamsDTO{}, err
+	}
+
+	return s.getUserTeams(ctx, user)
+}
+
+// GetReviews lists the pull requests the user was assigned to review.
+func (s *userService) GetReviews(ctx context.Context, query dto.UserPRsQueryDTO) (dto.UserPRsDTO, error) {
+	return s.listUserPRs(ctx, query, s.prRepo.GetByUserId)
+}
+
+// GetAuthored lists the pull requests the user opened.
+func (s *userService) GetAuthored(ctx context.Context, query dto.UserPRsQueryDTO) (dto.UserPRsDTO, error) {
+	return s.listUserPRs(ctx, query, s.prRepo.GetByAuthorId)
+}
+
+// listUserPRs returns one page of pull requests, newest first unless the
+// query asks for ascending order. One extra row is fetched to tell whether a
+// next page exists.
+func (s *userService) listUserPRs(
+	ctx context.Context,
+	query dto.UserPRsQueryDTO,
+	fetch func(ctx context.Context, userId string, filter domain.PullRequestFilter) ([]domain.PullRequest, error),
+) (dto.UserPRsDTO, error) {
+	user, err := s.getUser(ctx, query.UserID)
+	if err != nil {
+		return dto.UserPRsDTO{}, err
+	}
+
+	filter := domain.PullRequestFilter{
+		Status:      query.Status,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+		Descending:  query.Order != "asc",
+		Limit:       query.Limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = DEFAULT_USER_PR_LIST_LIMIT
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodePRCursor(query.Cursor)
+		if err != nil {
+			return dto.UserPRsDTO{}, err
+		}
+
+		filter.After = &cursor
+	}
+
+	pageSize := filter.Limit
+	filter.Limit++
+
+	prs, err := fetch(ctx, user.ID, filter)
+	if err != nil {
+		return dto.UserPRsDTO{}, err
+	}
+
+	var nextCursor string
+
+	if len(prs) > pageSize {
+		prs = prs[:pageSize]
+		last := prs[pageSize-1]
+		nextCursor = encodePRCursor(domain.PullRequestCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	prDTOs := make([]dto.PullRequestShortDTO, len(prs))
+	for i, pr := range prs {
+		prDTOs[i] = dto.PullRequestShortDTO{
+			ID:        pr.ID,
+			Name:      pr.Name,
+			Status:    pr.Status,
+			AuthorID:  pr.AuthorID,
+			Priority:  pr.Priority,
+			CreatedAt: pr.CreatedAt,
+			MergedAt:  pr.MergedAt,
+		}
+	}
+
+	return dto.UserPRsDTO{
+		UserID:       user.ID,
+		PullRequests: prDTOs,
+		NextCursor:   nextCursor,
+	}, nil
+}
+
+// GetReviewQueue lists what the reviewer should look at next. Ordering is
+// done by the database; the other reviewers of every PR and their verdicts
+// are loaded with one more query.
+func (s *userService) GetReviewQueue(
+	ctx context.Context,
+	query dto.UserReviewQueueQueryDTO,
+) (dto.UserReviewQueueDTO, error) {
+	user, err := s.getUser(ctx, query.UserID)
+	if err != nil {
+		return dto.UserReviewQueueDTO{}, err
+	}
+
+	items, err := s.prRepo.GetReviewQueue(ctx, user.ID, query.IncludeReviewed)
+	if err != nil {
+		return dto.UserReviewQueueDTO{}, err
+	}
+
+	prIds := make([]string, len(items))
+	for i, item := range items {
+		prIds[i] = item.PullRequestID
+	}
+
+	prReviewers, err := s.prReviewerRepo.GetByPRIds(ctx, prIds)
+	if err != nil {
+		return dto.UserReviewQueueDTO{}, err
+	}
+
+	otherReviewers := make(map[string][]dto.ReviewerVerdictDTO, len(items))
+	for _, prReviewer := range prReviewers {
+		if prReviewer.UserID == user.ID {
+			continue
+		}
+
+		otherReviewers[prReviewer.PullRequestID] = append(
+			otherReviewers[prReviewer.PullRequestID],
+			dto.ReviewerVerdictDTO{UserID: prReviewer.UserID, Verdict: prReviewer.Verdict},
+		)
+	}
+
+	queue := make([]dto.ReviewQueueItemDTO, len(items))
+	for i, item := range items {
+		queue[i] = dto.ReviewQueueItemDTO{
+			PullRequestID:   item.PullRequestID,
+			PullRequestName: item.PullRequestName,
+			AuthorID:        item.AuthorID,
+			Priority:        item.Priority,
+			CreatedAt:       item.CreatedAt,
+			AssignedAt:      item.AssignedAt,
+			Deadline:        item.Deadline,
+			Verdict:         item.Verdict,
+			OtherReviewers:  otherReviewers[item.PullRequestID],
+		}
+
+		if queue[i].OtherReviewers == nil {
+			queue[i].OtherReviewers = []dto.ReviewerVerdictDTO{}
+		}
+	}
+
+	return dto.UserReviewQueueDTO{
+		UserID:       user.ID,
+		PullRequests: queue,
+	}, nil
+}
+
+func (s *userService) GetUser(ctx context.Context, userId string) (dto.UserDTO, error) {
+	user, err := s.getUser(ctx, userId)
+	if err != nil {
+		return dto.UserDTO{}, err
+	}
+
+	teamName, err := s.getTeamName(ctx, user)
+	if err != nil {
+		return dto.UserDTO{}, err
+	}
+
+	return userToDTO(user, teamName), nil
+}
+
+// UpdateProfile changes the personal fields present in the request. Team,
+// role and activity are managed by their own endpoints.
+func (s *userService) UpdateProfile(ctx context.Context, updateDTO dto.UserUpdateDTO) (dto.UserDTO, error) {
+	user, err := s.getUser(ctx, updateDTO.UserID)
+	if err != nil {
+		return dto.UserDTO{}, err
+	}
+
+	if user.AnonymizedAt != nil {
+		return dto.UserDTO{}, appErrors.NewUserAnonymizedError(user.ID)
+	}
+
+	if updateDTO.Username != nil {
+		if *updateDTO.Username == "" {
+			return dto.UserDTO{}, appErrors.NewValidationFailedError("username cannot be empty")
+		}
+
+		user.Username = *updateDTO.Username
+	}
+
+	if updateDTO.Email != nil {
+		if *updateDTO.Email != "" && !isValidEmail(*updateDTO.Email) {
+			return dto.UserDTO{}, appErrors.NewValidationFailedError("email is not a valid address")
+		}
+
+		user.Email = emptyToNil(*updateDTO.Email)
+	}
+
+	if updateDTO.FullName != nil {
+		user.FullName = emptyToNil(*updateDTO.FullName)
+	}
+
+	user, err = s.userRepo.UpdateProfile(ctx, user)
+	if err != nil {
+		return dto.UserDTO{}, err
+	}
+
+	teamName, err := s.getTeamName(ctx, user)
+	if err != nil {
+		return dto.UserDTO{}, err
+	}
+
+	return userToDTO(user, teamName), nil
+}
+
+// Anonymize removes the personal data of a departed employee. The user row
+// is kept under a placeholder name so pull requests, reviews and statistics
+// still reference it. The user is deactivated, detached from every team and
+// their open reviews are reassigned. Anonymizing twice changes nothing.
+func (s *userService) Anonymize(ctx context.Context, userId string) (dto.UserAnonymizeResultDTO, error) {
+	user, err := s.getUser(ctx, userId)
+	if err != nil {
+		return dto.UserAnonymizeResultDTO{}, err
+	}
+
+	if user.AnonymizedAt != nil {
+		return dto.UserAnonymizeResultDTO{UserDTO: userToDTO(user, "")}, nil
+	}
+
+	var report dto.ReviewsReassignmentReportDTO
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		err := s.membershipRepo.DeleteByUserID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.identityRepo.DeleteByUserID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		if user.TeamID.Valid {
+			err = s.historyRepo.SaveBatch(ctx, []domain.TeamMembershipChange{
+				newMembershipChange(user.ID, user.TeamID, uuid.NullUUID{}),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		user, err = s.userRepo.Anonymize(ctx, user.ID, ANONYMIZED_USERNAME)
+		if err != nil {
+			return err
+		}
+
+		report, err = s.reviewerService.ReassignUsersReviews(ctx, []string{user.ID})
+
+		return err
+	})
+	if err != nil {
+		return dto.UserAnonymizeResultDTO{}, err
+	}
+
+	return dto.UserAnonymizeResultDTO{
+		UserDTO:      userToDTO(user, ""),
+		Reassignment: &report,
+	}, nil
+}
+
+// LinkIdentity attaches an external identity to the user. Linking an identity
+// the user already owns is a no-op.
+func (s *userService) LinkIdentity(ctx context.Context, linkDTO dto.UserIdentityLinkDTO) (dto.UserIdentitiesDTO, error) {
+	externalId, err := normalizeExternalID(linkDTO.Provider, linkDTO.ExternalID)
+	if err != nil {
+		return dto.UserIdentitiesDTO{}, err
+	}
+
+	user, err := s.getUser(ctx, linkDTO.UserID)
+	if err != nil {
+		return dto.UserIdentitiesDTO{}, err
+	}
+
+	if user.AnonymizedAt != nil {
+		return dto.UserIdentitiesDTO{}, appErrors.NewUserAnonymizedError(user.ID)
+	}
+
+	err = s.trManager.Do(ctx, func(ctx context.Context) error {
+		identity, err := s.identityRepo.GetByExternalID(ctx, linkDTO.Provider, externalId)
+		if err == nil {
+			if identity.UserID != user.ID {
+				return appErrors.NewIdentityLinkedError(string(linkDTO.Provider), externalId)
+			}
+
+			return nil
+		}
+
+		if !errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return err
+		}
+
+		_, err = s.identityRepo.Save(ctx, domain.UserIdentity{
+			Provider:   linkDTO.Provider,
+			ExternalID: externalId,
+			UserID:     user.ID,
+		})
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrAlreadyExists) {
+			return appErrors.NewIdentityLinkedError(string(linkDTO.Provider), externalId)
+		}
+
+		return err
+	})
+	if err != nil {
+		return dto.UserIdentitiesDTO{}, err
+	}
+
+	return s.getIdentities(ctx, user.ID)
+}
+
+func (s *userService) UnlinkIdentity(ctx context.Context, linkDTO dto.UserIdentityLinkDTO) (dto.UserIdentitiesDTO, error) {
+	externalId, err := normalizeExternalID(linkDTO.Provider, linkDTO.ExternalID)
+	if err != nil {
+		return dto.UserIdentitiesDTO{}, err
+	}
+
+	user, err := s.getUser(ctx, linkDTO.UserID)
+	if err != nil {
+		return dto.UserIdentitiesDTO{}, err
+	}
+
+	err = s.identityRepo.Delete(ctx, domain.UserIdentity{
+		Provider:   linkDTO.Provider,
+		ExternalID: externalId,
+		UserID:     user.ID,
+	})
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.UserIdentitiesDTO{}, appErrors.NewNotFoundError(
+				string(linkDTO.Provider) + " identity '" + externalId + "' of user '" + user.ID + "'",
+			)
+		}
+
+		return dto.UserIdentitiesDTO{}, err
+	}
+
+	return s.getIdentities(ctx, user.ID)
+}
+
+func (s *userService) GetIdentities(ctx context.Context, userId string) (dto.UserIdentitiesDTO, error) {
+	user, err := s.getUser(ctx, userId)
+	if err != nil {
+		return dto.UserIdentitiesDTO{}, err
+	}
+
+	return s.getIdentities(ctx, user.ID)
+}
+
+// ResolveIdentity finds the user behind an external identity so integrations
+// don't have to keep their own mapping.
+func (s *userService) ResolveIdentity(ctx context.Context, query dto.UserResolveQueryDTO) (dto.UserDTO, error) {
+	externalId, err := normalizeExternalID(query.Provider, query.ExternalID)
+	if err != nil {
+		return dto.UserDTO{}, err
+	}
+
+	identity, err := s.identityRepo.GetByExternalID(ctx, query.Provider, externalId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return dto.UserDTO{}, appErrors.NewNotFoundError(string(query.Provider) + " identity '" + externalId + "'")
+		}
+
+		return dto.UserDTO{}, err
+	}
+
+	return s.GetUser(ctx, identity.UserID)
+}
+
+func (s *userService) IncrementAssignRate(ctx context.Context, userId string) (domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return domain.User{}, appErrors.NewNotFoundError("User with ID '" + userId + "'")
+		}
+
+		return domain.User{}, err
+	}
+
+	user.AssignRate += 1
+
+	updatedUser, err := s.userRepo.Update(ctx, user)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return updatedUser, nil
+}
+
+func (s *userService) getUser(ctx context.Context, userId string) (domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return domain.User{}, appErrors.NewNotFoundError("User with ID '" + userId + "'")
+		}
+
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) getIdentities(ctx context.Context, userId string) (dto.UserIdentitiesDTO, error) {
+	identities, err := s.identityRepo.GetByUserID(ctx, userId)
+	if err != nil {
+		return dto.UserIdentitiesDTO{}, err
+	}
+
+	identityDTOs := make([]dto.UserIdentityDTO, len(identities))
+	for i, identity := range identities {
+		identityDTOs[i] = dto.UserIdentityDTO{
+			Provider:   identity.Provider,
+			ExternalID: identity.ExternalID,
+		}
+	}
+
+	return dto.UserIdentitiesDTO{
+		UserID:     userId,
+		Identities: identityDTOs,
+	}, nil
+}
+
+// isValidEmail accepts a bare address only, without a display name.
+func isValidEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+
+	return err == nil && address.Address == email
+}
+
+// normalizeExternalID brings an external id to the form it is stored in.
+// GitHub logins and emails are case-insensitive, GitLab ids are numeric.
+func normalizeExternalID(provider dto.IdentityProvider, externalId string) (string, error) {
+	externalId = strings.TrimSpace(externalId)
+	if externalId == "" {
+		return "", appErrors.NewValidationFailedError("external_id cannot be empty")
+	}
+
+	switch provider {
+	case dto.IdentityGitHub, dto.IdentityEmail:
+		return strings.ToLower(externalId), nil
+	case dto.IdentityGitLab:
+		if strings.Trim(externalId, "0123456789") != "" {
+			return "", appErrors.NewValidationFailedError("GitLab user id must be numeric")
+		}
+
+		return externalId, nil
+	default:
+		return externalId, nil
+	}
+}
+
+// getTeamName returns an empty name for users that were removed from their team.
+func (s *userService) getTeamName(ctx context.Context, user domain.User) (string, error) {
+	if !user.TeamID.Valid {
+		return "", nil
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, user.TeamID.UUID)
+	if err != nil {
+		return "", err
+	}
+
+	return team.Name, nil
+}
+
+func (s *userService) getUserAndTeam(
+	ctx context.Context,
+	membershipDTO dto.UserTeamMembershipDTO,
+) (domain.User, domain.Team, error) {
+	user, err := s.userRepo.GetByID(ctx, membershipDTO.UserID)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return domain.User{}, domain.Team{}, appErrors.NewNotFoundError("User with ID '" + membershipDTO.UserID + "'")
+		}
+
+		return domain.User{}, domain.Team{}, err
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, membershipDTO.TeamName)
+	if err != nil {
+		if errors.Is(appErrors.MapPgError(err), appErrors.ErrNotFound) {
+			return domain.User{}, domain.Team{}, appErrors.NewNotFoundError("Team with name '" + membershipDTO.TeamName + "'")
+		}
+
+		return domain.User{}, domain.Team{}, err
+	}
+
+	return user, team, nil
+}
+
+func (s *userService) getUserTeams(ctx context.Context, user domain.User) (dto.UserTeamsDTO, error) {
+	primaryTeamName, err := s.getTeamName(ctx, user)
+	if err != nil {
+		return dto.UserTeamsDTO{}, err
+	}
+
+	memberships, err := s.membershipRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return dto.UserTeamsDTO{}, err
+	}
+
+	teamNames := make([]string, len(memberships))
+	for i, membership := range memberships {
+		team, err := s.teamRepo.GetByID(ctx, membership.TeamID)
+		if err != nil {
+			return dto.UserTeamsDTO{}, err
+		}
+
+		teamNames[i] = team.Name
+	}
+
+	return dto.UserTeamsDTO{
+		UserID:              user.ID,
+		PrimaryTeamName:     primaryTeamName,
+		AdditionalTeamNames: teamNames,
+	}, nil
+}
+
+func newMembershipChange(userId string, fromTeamId uuid.NullUUID, toTeamId uuid.NullUUID) domain.TeamMembershipChange {
+	return domain.TeamMembershipChange{
+		UserID:     userId,
+		FromTeamID: fromTeamId,
+		ToTeamID:   toTeamId,
+		ChangedAt:  time.Now().UTC(),
+	}
+}
+
+func userToDTO(user domain.User, teamName string) dto.UserDTO {
+	return dto.UserDTO{
+		ID:           user.ID,
+		Username:     user.Username,
+		TeamName:     teamName,
+		IsActive:     user.IsActive,
+		Email:        user.Email,
+		FullName:     user.FullName,
+		AnonymizedAt: user.AnonymizedAt,
+	}
+}
+
+// encodePRCursor packs the listing position into an opaque URL-safe token.
+func encodePRCursor(cursor domain.PullRequestCursor) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID),
+	)
+}
+
+func decodePRCursor(token string) (domain.PullRequestCursor, error) {
+	invalidCursorErr := appErrors.NewValidationFailedError("cursor '" + token + "' is invalid")
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return domain.PullRequestCursor{}, invalidCursorErr
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return domain.PullRequestCursor{}, invalidCursorErr
+	}
+
+	cursorTime, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return domain.PullRequestCursor{}, invalidCursorErr
+	}
+
+	return domain.PullRequestCursor{CreatedAt: cursorTime, ID: id}, nil
+}
+
+func emptyToNil(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func memberDTOtoUser(memberDTO dto.TeamMemberDTO, teamId uuid.UUID) domain.User {
+	return domain.User{
+		ID:       memberDTO.ID,
+		Username: memberDTO.Username,
+		IsActive: *memberDTO.IsActive,
+		TeamID:   uuid.NullUUID{UUID: teamId, Valid: true},
+		Role:     roleOrDefault(memberDTO.Role),
+	}
+}
+
+func userToMemberDTO(user domain.User) dto.TeamMemberDTO {
+	return dto.TeamMemberDTO{
+		ID:       user.ID,
+		Username: user.Username,
+		IsActive: &user.IsActive,
+		Role:     user.Role,
+	}
+}
+
+func roleOrDefault(role dto.TeamRole) dto.TeamRole {
+	if role == "" {
+		return dto.TeamRoleMember
+	}
+
+	return role
 }
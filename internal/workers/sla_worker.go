@@ -0,0 +1,51 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type SLAService interface {
+	ProcessOverdueReviews(ctx context.Context) (int, error)
+}
+
+type SLAWorker struct {
+	service  SLAService
+	interval time.Duration
+}
+
+func NewSLAWorker(service SLAService, interval time.Duration) *SLAWorker {
+	return &SLAWorker{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Run checks for overdue reviews every interval until ctx is cancelled.
+func (w *SLAWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	slog.Info("SLA worker started", slog.String("interval", w.interval.String()))
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("SLA worker stopped")
+
+			return
+		case <-ticker.C:
+			processed, err := w.service.ProcessOverdueReviews(ctx)
+			if err != nil {
+				slog.Error("Failed to process overdue reviews", slog.String("error", err.Error()))
+
+				continue
+			}
+
+			if processed > 0 {
+				slog.Info("Processed overdue reviews", slog.Int("count", processed))
+			}
+		}
+	}
+}
@@ -0,0 +1,2 @@
+DROP INDEX idx_users_team_id;
+DROP INDEX idx_pull_request_reviewers_pull_request_id;
\ No newline at end of file
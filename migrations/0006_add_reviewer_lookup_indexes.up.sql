@@ -0,0 +1,2 @@
+CREATE INDEX idx_pull_request_reviewers_pull_request_id ON pull_request_reviewers(pull_request_id);
+CREATE INDEX idx_users_team_id ON users(team_id);
\ No newline at end of file
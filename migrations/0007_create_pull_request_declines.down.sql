@@ -0,0 +1 @@
+DROP TABLE pull_request_declines;
\ No newline at end of file
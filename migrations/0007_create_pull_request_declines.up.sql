@@ -0,0 +1,7 @@
+CREATE TABLE pull_request_declines (
+    pull_request_id VARCHAR(50) NOT NULL REFERENCES pull_requests(id),
+    user_id VARCHAR(50) NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    declined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (pull_request_id, user_id)
+);
\ No newline at end of file
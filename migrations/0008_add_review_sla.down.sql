@@ -0,0 +1,7 @@
+DROP TABLE review_sla_events;
+
+ALTER TABLE pull_request_reviewers
+DROP COLUMN sla_breached_at,
+DROP COLUMN assigned_at;
+
+DROP TABLE team_sla_policies;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE team_sla_policies (
+    team_id UUID PRIMARY KEY REFERENCES teams(id),
+    review_sla_seconds BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    backup_reviewer_id VARCHAR(50) REFERENCES users(id)
+);
+
+ALTER TABLE pull_request_reviewers
+ADD COLUMN assigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+ADD COLUMN sla_breached_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE review_sla_events (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(50) NOT NULL REFERENCES pull_requests(id),
+    reviewer_id VARCHAR(50) NOT NULL REFERENCES users(id),
+    new_reviewer_id VARCHAR(50) REFERENCES users(id),
+    action TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
\ No newline at end of file
@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE team_id IS NULL) THEN
+        RAISE EXCEPTION 'cannot restore NOT NULL on users.team_id: users without a team exist, assign them to a team first';
+    END IF;
+END
+$$;
+
+ALTER TABLE users ALTER COLUMN team_id SET NOT NULL;
@@ -0,0 +1,9 @@
+ALTER TABLE team_sla_policies
+DROP CONSTRAINT team_sla_policies_backup_reviewer_id_fkey,
+ADD CONSTRAINT team_sla_policies_backup_reviewer_id_fkey
+    FOREIGN KEY (backup_reviewer_id) REFERENCES users(id),
+DROP CONSTRAINT team_sla_policies_team_id_fkey,
+ADD CONSTRAINT team_sla_policies_team_id_fkey
+    FOREIGN KEY (team_id) REFERENCES teams(id);
+
+ALTER TABLE teams DROP COLUMN archived_at;
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE teams ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE team_sla_policies
+DROP CONSTRAINT team_sla_policies_team_id_fkey,
+ADD CONSTRAINT team_sla_policies_team_id_fkey
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+DROP CONSTRAINT team_sla_policies_backup_reviewer_id_fkey,
+ADD CONSTRAINT team_sla_policies_backup_reviewer_id_fkey
+    FOREIGN KEY (backup_reviewer_id) REFERENCES users(id) ON DELETE SET NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE team_membership_history;
\ No newline at end of file
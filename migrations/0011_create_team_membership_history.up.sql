@@ -0,0 +1,9 @@
+CREATE TABLE team_membership_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_team_id UUID REFERENCES teams(id) ON DELETE SET NULL,
+    to_team_id UUID REFERENCES teams(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_team_membership_history_user_id ON team_membership_history(user_id);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN parent_id UUID REFERENCES teams(id) ON DELETE SET NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests DROP COLUMN team_id;
+
+DROP TABLE team_memberships;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE team_memberships (
+    user_id VARCHAR(50) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, team_id)
+);
+
+CREATE INDEX idx_team_memberships_team_id ON team_memberships(team_id);
+
+ALTER TABLE pull_requests ADD COLUMN team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
\ No newline at end of file
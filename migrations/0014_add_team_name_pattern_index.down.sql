@@ -0,0 +1 @@
+DROP INDEX idx_teams_name_pattern;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX idx_teams_name_pattern ON teams(name varchar_pattern_ops);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE team_memberships DROP COLUMN role;
+
+ALTER TABLE users DROP COLUMN role;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('lead', 'member', 'observer'));
+
+ALTER TABLE team_memberships ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('lead', 'member', 'observer'));
\ No newline at end of file
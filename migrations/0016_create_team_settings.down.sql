@@ -0,0 +1 @@
+DROP TABLE team_settings;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE team_settings (
+    team_id UUID PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    reviewers_count INT NOT NULL DEFAULT 2 CHECK (reviewers_count BETWEEN 1 AND 5),
+    strategy VARCHAR(20) NOT NULL DEFAULT 'LEAST_LOADED'
+        CHECK (strategy IN ('LEAST_LOADED', 'RANDOM')),
+    own_team_rule VARCHAR(20) NOT NULL DEFAULT 'PREFERRED'
+        CHECK (own_team_rule IN ('PREFERRED', 'ONLY', 'EXCLUDED')),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
\ No newline at end of file
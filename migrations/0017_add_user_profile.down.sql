@@ -0,0 +1,4 @@
+ALTER TABLE users
+DROP COLUMN anonymized_at,
+DROP COLUMN full_name,
+DROP COLUMN email;
\ No newline at end of file
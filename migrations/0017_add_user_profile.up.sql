@@ -0,0 +1,4 @@
+ALTER TABLE users
+ADD COLUMN email VARCHAR(255),
+ADD COLUMN full_name VARCHAR(255),
+ADD COLUMN anonymized_at TIMESTAMP WITH TIME ZONE;
\ No newline at end of file
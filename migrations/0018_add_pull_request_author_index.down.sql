@@ -0,0 +1 @@
+DROP INDEX idx_pull_requests_author_id_created_at;
\ No newline at end of file
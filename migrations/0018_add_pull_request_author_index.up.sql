@@ -0,0 +1 @@
+CREATE INDEX idx_pull_requests_author_id_created_at ON pull_requests(author_id, created_at, id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE pull_request_reviewers
+DROP COLUMN verdict_at,
+DROP COLUMN verdict;
+
+ALTER TABLE pull_requests DROP COLUMN priority;
\ No newline at end of file
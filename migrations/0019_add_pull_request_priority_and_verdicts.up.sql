@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests ADD COLUMN priority VARCHAR(10) NOT NULL DEFAULT 'NORMAL'
+    CHECK (priority IN ('LOW', 'NORMAL', 'HIGH', 'URGENT'));
+
+ALTER TABLE pull_request_reviewers
+ADD COLUMN verdict VARCHAR(20) CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED')),
+ADD COLUMN verdict_at TIMESTAMP WITH TIME ZONE;
\ No newline at end of file
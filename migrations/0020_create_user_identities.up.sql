@@ -0,0 +1,9 @@
+CREATE TABLE user_identities (
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('GITHUB', 'GITLAB', 'SLACK', 'EMAIL')),
+    external_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, external_id)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
\ No newline at end of file
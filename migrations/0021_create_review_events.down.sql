@@ -0,0 +1 @@
+DROP TABLE review_events;
\ No newline at end of file
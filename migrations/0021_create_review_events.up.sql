@@ -0,0 +1,25 @@
+CREATE TABLE review_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('ASSIGNED', 'UNASSIGNED', 'MERGED')),
+    pull_request_id VARCHAR(50) NOT NULL REFERENCES pull_requests(id),
+    user_id VARCHAR(50) NOT NULL REFERENCES users(id),
+    team_id UUID REFERENCES teams(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_review_events_created_at ON review_events(created_at);
+CREATE INDEX idx_review_events_user_id_created_at ON review_events(user_id, created_at);
+CREATE INDEX idx_review_events_team_id_created_at ON review_events(team_id, created_at);
+
+INSERT INTO review_events (event_type, pull_request_id, user_id, team_id, created_at)
+SELECT 'ASSIGNED', prr.pull_request_id, prr.user_id, COALESCE(pr.team_id, a.team_id), prr.assigned_at
+FROM pull_request_reviewers prr
+JOIN pull_requests pr ON pr.id = prr.pull_request_id
+JOIN users a ON a.id = pr.author_id;
+
+INSERT INTO review_events (event_type, pull_request_id, user_id, team_id, created_at)
+SELECT 'MERGED', prr.pull_request_id, prr.user_id, COALESCE(pr.team_id, a.team_id), pr.merged_at
+FROM pull_request_reviewers prr
+JOIN pull_requests pr ON pr.id = prr.pull_request_id
+JOIN users a ON a.id = pr.author_id
+WHERE pr.status = 'MERGED' AND pr.merged_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX idx_review_events_pull_request_id;
+
+ALTER TABLE pull_requests
+DROP COLUMN required_reviewers,
+DROP COLUMN initial_reviewers;
\ No newline at end of file
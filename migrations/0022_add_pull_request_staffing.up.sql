@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests
+ADD COLUMN required_reviewers INT,
+ADD COLUMN initial_reviewers INT;
+
+CREATE INDEX idx_review_events_pull_request_id ON review_events(pull_request_id);
\ No newline at end of file
@@ -0,0 +1,10 @@
+package dto
+
+type IdentityProvider string
+
+const (
+	IdentityGitHub IdentityProvider = "GITHUB"
+	IdentityGitLab IdentityProvider = "GITLAB"
+	IdentitySlack  IdentityProvider = "SLACK"
+	IdentityEmail  IdentityProvider = "EMAIL"
+)
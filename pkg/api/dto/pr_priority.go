@@ -0,0 +1,17 @@
+package dto
+
+type PRPriority string
+
+const (
+	PriorityLow    PRPriority = "LOW"
+	PriorityNormal PRPriority = "NORMAL"
+	PriorityHigh   PRPriority = "HIGH"
+	PriorityUrgent PRPriority = "URGENT"
+)
+
+type ReviewVerdict string
+
+const (
+	VerdictApproved         ReviewVerdict = "APPROVED"
+	VerdictChangesRequested ReviewVerdict = "CHANGES_REQUESTED"
+)
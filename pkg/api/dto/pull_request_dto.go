@@ -5,9 +5,11 @@ import (
 )
 
 type PullRequestCreateDTO struct {
-	ID       string `binding:"required,min=1,max=50" json:"pull_request_id"`
-	Name     string `binding:"required"              json:"pull_request_name"`
-	AuthorID string `binding:"required"              json:"author_id"`
+	ID       string     `binding:"required,min=1,max=50"                  json:"pull_request_id"`
+	Name     string     `binding:"required"                               json:"pull_request_name"`
+	AuthorID string     `binding:"required"                               json:"author_id"`
+	TeamName *string    `binding:"omitempty,min=1,max=50"                 json:"team_name,omitempty"`
+	Priority PRPriority `binding:"omitempty,oneof=LOW NORMAL HIGH URGENT" json:"priority,omitempty"`
 }
 
 type PullRequestDTO struct {
@@ -15,6 +17,7 @@ type PullRequestDTO struct {
 	Name      string     `json:"pull_request_name"`
 	AuthorID  string     `json:"author_id"`
 	Status    PRStatus   `json:"status"`
+	Priority  PRPriority `json:"priority"`
 	CreatedAt time.Time  `json:"created_at"`
 	MergedAt  *time.Time `json:"merged_at,omitempty"`
 	Reviewers []string   `json:"assigned_reviewers"`
@@ -25,10 +28,13 @@ type FullPullRequestDTO struct {
 }
 
 type PullRequestShortDTO struct {
-	ID       string   `json:"pull_request_id"`
-	Name     string   `json:"pull_request_name"`
-	AuthorID string   `json:"author_id"`
-	Status   PRStatus `json:"status"`
+	ID        string     `json:"pull_request_id"`
+	Name      string     `json:"pull_request_name"`
+	AuthorID  string     `json:"author_id"`
+	Status    PRStatus   `json:"status"`
+	Priority  PRPriority `json:"priority"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at,omitempty"`
 }
 
 type PullRequestMergeDTO struct {
@@ -39,3 +45,43 @@ type PullRequestReassignDTO struct {
 	PullRequestID string `binding:"required,min=1,max=50" json:"pull_request_id"`
 	OldReviewerID string `binding:"required,min=1,max=50" json:"old_reviewer_id"`
 }
+
+type PullRequestOverrideReviewerDTO struct {
+	PullRequestID string `binding:"required,min=1,max=50" json:"pull_request_id"`
+	LeadID        string `binding:"required,min=1,max=50" json:"lead_id"`
+	OldReviewerID string `binding:"required,min=1,max=50" json:"old_reviewer_id"`
+	NewReviewerID string `binding:"required,min=1,max=50" json:"new_reviewer_id"`
+}
+
+type PullRequestSetPriorityDTO struct {
+	PullRequestID string     `binding:"required,min=1,max=50"                 json:"pull_request_id"`
+	Priority      PRPriority `binding:"required,oneof=LOW NORMAL HIGH URGENT" json:"priority"`
+}
+
+type PullRequestVerdictDTO struct {
+	PullRequestID string        `binding:"required,min=1,max=50"                     json:"pull_request_id"`
+	ReviewerID    string        `binding:"required,min=1,max=50"                     json:"reviewer_id"`
+	Verdict       ReviewVerdict `binding:"required,oneof=APPROVED CHANGES_REQUESTED" json:"verdict"`
+}
+
+type PullRequestDeclineDTO struct {
+	PullRequestID string `binding:"required,min=1,max=50"  json:"pull_request_id"`
+	ReviewerID    string `binding:"required,min=1,max=50"  json:"reviewer_id"`
+	Reason        string `binding:"required,min=1,max=500" json:"reason"`
+}
+
+type PullRequestDeclineResultDTO struct {
+	PullRequest PullRequestDTO `json:"pr"`
+	ReplacedBy  string         `json:"replaced_by,omitempty"`
+}
+
+type ReviewReassignmentDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+}
+
+type ReviewsReassignmentReportDTO struct {
+	Reassigned  []ReviewReassignmentDTO `json:"reassigned"`
+	NoCandidate []ReviewReassignmentDTO `json:"no_candidate"`
+}
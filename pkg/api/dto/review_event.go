@@ -0,0 +1,17 @@
+package dto
+
+type ReviewEventType string
+
+const (
+	EventAssigned   ReviewEventType = "ASSIGNED"
+	EventUnassigned ReviewEventType = "UNASSIGNED"
+	EventMerged     ReviewEventType = "MERGED"
+)
+
+type StatisticGroupBy string
+
+const (
+	GroupByDay   StatisticGroupBy = "day"
+	GroupByWeek  StatisticGroupBy = "week"
+	GroupByMonth StatisticGroupBy = "month"
+)
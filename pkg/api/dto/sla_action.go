@@ -0,0 +1,16 @@
+package dto
+
+type SLAAction string
+
+const (
+	SLAActionReassign SLAAction = "REASSIGN"
+	SLAActionEscalate SLAAction = "ESCALATE"
+)
+
+type SLAEventAction string
+
+const (
+	SLAEventReassigned  SLAEventAction = "REASSIGNED"
+	SLAEventEscalated   SLAEventAction = "ESCALATED"
+	SLAEventNoCandidate SLAEventAction = "NO_CANDIDATE"
+)
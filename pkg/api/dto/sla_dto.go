@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+)
+
+type TeamSLAPolicyDTO struct {
+	TeamName         string    `binding:"required,min=1,max=50"           json:"team_name"`
+	ReviewSLA        string    `binding:"required"                        json:"review_sla"`
+	Action           SLAAction `binding:"required,oneof=REASSIGN ESCALATE" json:"action"`
+	BackupReviewerID *string   `binding:"omitempty,min=1,max=50"          json:"backup_reviewer_id,omitempty"`
+}
+
+type OverdueReviewDTO struct {
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	ReviewerID      string     `json:"reviewer_id"`
+	AssignedAt      time.Time  `json:"assigned_at"`
+	Deadline        time.Time  `json:"deadline"`
+	SLABreachedAt   *time.Time `json:"sla_breached_at,omitempty"`
+}
+
+type OverdueReviewsDTO struct {
+	OverdueReviews []OverdueReviewDTO `json:"overdue_reviews"`
+}
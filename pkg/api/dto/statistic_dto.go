@@ -1,10 +1,212 @@
 package dto
 
+import (
+	"time"
+)
+
+// StatisticPeriodQueryDTO limits statistics to review events between from
+// (inclusive) and to (exclusive) and optionally splits them into periods.
+type StatisticPeriodQueryDTO struct {
+	From    *time.Time       `form:"from"                              time_format:"2006-01-02T15:04:05Z07:00"`
+	To      *time.Time       `form:"to"                                time_format:"2006-01-02T15:04:05Z07:00"`
+	GroupBy StatisticGroupBy `binding:"omitempty,oneof=day week month" form:"group_by"`
+}
+
+type ActivityPointDTO struct {
+	PeriodStart time.Time `json:"period_start"`
+	Assigned    int       `json:"assigned"`
+	Unassigned  int       `json:"unassigned"`
+	Merged      int       `json:"merged"`
+}
+
+// ActivityDTO counts review events of a user or a team in the requested
+// period. Series is only filled when the events are grouped.
+type ActivityDTO struct {
+	Assigned   int                `json:"assigned"`
+	Unassigned int                `json:"unassigned"`
+	Merged     int                `json:"merged"`
+	Series     []ActivityPointDTO `json:"series,omitempty"`
+}
+
 type UserStatisticDTO struct {
-	UserID     string `json:"user_id"`
-	AssignRate int    `json:"assign_rate"`
+	UserID     string       `json:"user_id"`
+	AssignRate int          `json:"assign_rate"`
+	Activity   *ActivityDTO `json:"activity,omitempty"`
 }
 
 type AllUsersStatisticDTO struct {
 	Statistics []UserStatisticDTO `json:"user_statistics"`
 }
+
+type DeclineDTO struct {
+	PullRequestID string    `json:"pull_request_id"`
+	Reason        string    `json:"reason"`
+	DeclinedAt    time.Time `json:"declined_at"`
+}
+
+type UserDeclineStatisticDTO struct {
+	UserID       string       `json:"user_id"`
+	DeclineCount int          `json:"decline_count"`
+	Declines     []DeclineDTO `json:"declines"`
+}
+
+type AllDeclinesStatisticDTO struct {
+	Statistics []UserDeclineStatisticDTO `json:"decline_statistics"`
+}
+
+type TeamsStatisticQueryDTO struct {
+	StatisticPeriodQueryDTO
+
+	IncludeArchived bool `form:"include_archived"`
+}
+
+type TeamStatisticDTO struct {
+	TeamName          string       `json:"team_name"`
+	Archived          bool         `json:"archived"`
+	PRsCreated        int          `json:"prs_created"`
+	PRsMerged         int          `json:"prs_merged"`
+	PRsOpen           int          `json:"prs_open"`
+	AvgReviewersPerPR float64      `json:"avg_reviewers_per_pr"`
+	ActiveMembers     int          `json:"active_members"`
+	Activity          *ActivityDTO `json:"activity,omitempty"`
+}
+
+type AllTeamsStatisticDTO struct {
+	Statistics []TeamStatisticDTO `json:"team_statistics"`
+}
+
+type TeamMemberStatisticDTO struct {
+	UserID          string       `json:"user_id"`
+	Username        string       `json:"username"`
+	Role            TeamRole     `json:"role"`
+	IsActive        bool         `json:"is_active"`
+	AssignRate      int          `json:"assign_rate"`
+	PRsCreated      int          `json:"prs_created"`
+	PRsMerged       int          `json:"prs_merged"`
+	PRsOpen         int          `json:"prs_open"`
+	ReviewsAssigned int          `json:"reviews_assigned"`
+	OpenReviews     int          `json:"open_reviews"`
+	Activity        *ActivityDTO `json:"activity,omitempty"`
+}
+
+type TeamDetailedStatisticDTO struct {
+	TeamStatisticDTO
+
+	Members []TeamMemberStatisticDTO `json:"members"`
+}
+
+// PRLifecycleQueryDTO limits lifecycle statistics to PRs created between from
+// (inclusive) and to (exclusive).
+type PRLifecycleQueryDTO struct {
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   *time.Time `form:"to"   time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type MergeTimeDTO struct {
+	AvgSeconds float64 `json:"avg_seconds"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P95Seconds float64 `json:"p95_seconds"`
+}
+
+type PRLifecycleStatisticDTO struct {
+	PRsCreated            int           `json:"prs_created"`
+	PRsMerged             int           `json:"prs_merged"`
+	TimeToMerge           *MergeTimeDTO `json:"time_to_merge"`
+	Reassignments         int           `json:"reassignments"`
+	AvgReassignmentsPerPR float64       `json:"avg_reassignments_per_pr"`
+	MaxReassignmentsPerPR int           `json:"max_reassignments_per_pr"`
+	UnderstaffedPRs       int           `json:"understaffed_prs"`
+	UnderstaffedShare     *float64      `json:"understaffed_share"`
+}
+
+type TeamPRLifecycleStatisticDTO struct {
+	PRLifecycleStatisticDTO
+
+	TeamName string `json:"team_name"`
+}
+
+type AuthorPRLifecycleStatisticDTO struct {
+	PRLifecycleStatisticDTO
+
+	AuthorID string `json:"author_id"`
+}
+
+type AllPRLifecycleStatisticDTO struct {
+	Overall PRLifecycleStatisticDTO         `json:"overall"`
+	Teams   []TeamPRLifecycleStatisticDTO   `json:"teams"`
+	Authors []AuthorPRLifecycleStatisticDTO `json:"authors"`
+}
+
+// PRLifecycleRowDTO is one lifecycle group as a flat row of the CSV and NDJSON
+// exports. Group is overall, team or author; Key is the team name or the
+// author id.
+type PRLifecycleRowDTO struct {
+	PRLifecycleStatisticDTO
+
+	Group string `json:"group"`
+	Key   string `json:"key"`
+}
+
+// FairnessQueryDTO limits fairness metrics to assignments between from
+// (inclusive) and to (exclusive). Threshold overrides the configured Gini
+// coefficient above which a team is flagged.
+type FairnessQueryDTO struct {
+	From      *time.Time `form:"from"                     time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to"                       time_format:"2006-01-02T15:04:05Z07:00"`
+	Threshold *float64   `binding:"omitempty,min=0,max=1" form:"threshold"`
+}
+
+type TeamFairnessDTO struct {
+	TeamName     string   `json:"team_name"`
+	Members      int      `json:"members"`
+	TotalReviews int      `json:"total_reviews"`
+	MinLoad      int      `json:"min_load"`
+	MaxLoad      int      `json:"max_load"`
+	MeanLoad     float64  `json:"mean_load"`
+	StdDev       float64  `json:"stddev"`
+	Gini         float64  `json:"gini"`
+	MaxMinRatio  *float64 `json:"max_min_ratio"`
+	Unfair       bool     `json:"unfair"`
+}
+
+type AllTeamsFairnessDTO struct {
+	Threshold float64           `json:"threshold"`
+	Teams     []TeamFairnessDTO `json:"teams"`
+}
+
+type ReviewGraphFormat string
+
+const (
+	GraphFormatJSON    ReviewGraphFormat = "json"
+	GraphFormatDOT     ReviewGraphFormat = "dot"
+	GraphFormatGraphML ReviewGraphFormat = "graphml"
+)
+
+// ReviewGraphQueryDTO limits the review graph to PRs of the team and to
+// reviewers assigned between from (inclusive) and to (exclusive). Format
+// overrides the format negotiated from the Accept header.
+type ReviewGraphQueryDTO struct {
+	TeamName string            `binding:"omitempty,max=50"                 form:"team_name"`
+	From     *time.Time        `form:"from"                                time_format:"2006-01-02T15:04:05Z07:00"`
+	To       *time.Time        `form:"to"                                  time_format:"2006-01-02T15:04:05Z07:00"`
+	Format   ReviewGraphFormat `binding:"omitempty,oneof=json dot graphml" form:"format"`
+}
+
+type ReviewGraphNodeDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// ReviewGraphEdgeDTO points from the reviewer to the author, weighted by the
+// number of the author's PRs the reviewer is assigned to.
+type ReviewGraphEdgeDTO struct {
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+	Reviews    int    `json:"reviews"`
+}
+
+type ReviewGraphDTO struct {
+	Nodes []ReviewGraphNodeDTO `json:"nodes"`
+	Edges []ReviewGraphEdgeDTO `json:"edges"`
+}
@@ -1,12 +1,123 @@
 package dto
 
+import (
+	"time"
+)
+
 type TeamDTO struct {
-	Name    string          `binding:"required,min=1,max=50" json:"team_name"`
-	Members []TeamMemberDTO `binding:"required,dive"         json:"members"`
+	Name           string          `binding:"required,min=1,max=50"  json:"team_name"`
+	ParentTeamName *string         `binding:"omitempty,min=1,max=50" json:"parent_team_name,omitempty"`
+	Members        []TeamMemberDTO `binding:"required,dive"          json:"members"`
 }
 
 type TeamMemberDTO struct {
-	ID       string `binding:"required,min=1,max=50" json:"user_id"`
-	Username string `binding:"required"              json:"username"`
-	IsActive *bool  `binding:"required"              json:"is_active"`
+	ID       string   `binding:"required,min=1,max=50"              json:"user_id"`
+	Username string   `binding:"required"                           json:"username"`
+	IsActive *bool    `binding:"required"                           json:"is_active"`
+	Role     TeamRole `binding:"omitempty,oneof=lead member observer" json:"role"`
+}
+
+// TeamDeactivateUsersDTO is a lead action when ActingUserID is set. Without
+// it the request is administrative and team roles are not checked.
+type TeamDeactivateUsersDTO struct {
+	TeamName     string   `binding:"required,min=1,max=50"          json:"team_name"`
+	UserIDs      []string `binding:"omitempty,dive,required,max=50" json:"user_ids"`
+	ActingUserID *string  `binding:"omitempty,min=1,max=50"         json:"acting_user_id,omitempty"`
+}
+
+type TeamDeactivateUsersResultDTO struct {
+	TeamName       string                       `json:"team_name"`
+	DeactivatedIDs []string                     `json:"deactivated_user_ids"`
+	Reassignment   ReviewsReassignmentReportDTO `json:"reassignment"`
+}
+
+// TeamMembersAddDTO is checked against team roles only when ActingUserID is
+// set, like TeamDeactivateUsersDTO.
+type TeamMembersAddDTO struct {
+	TeamName     string          `binding:"required,min=1,max=50"  json:"team_name"`
+	Members      []TeamMemberDTO `binding:"required,min=1,dive"    json:"members"`
+	ActingUserID *string         `binding:"omitempty,min=1,max=50" json:"acting_user_id,omitempty"`
+}
+
+// TeamMembersRemoveDTO requires the acting user, if any, to lead the team.
+type TeamMembersRemoveDTO struct {
+	TeamName     string   `binding:"required,min=1,max=50"              json:"team_name"`
+	UserIDs      []string `binding:"required,min=1,dive,required,max=50" json:"user_ids"`
+	ActingUserID *string  `binding:"omitempty,min=1,max=50"             json:"acting_user_id,omitempty"`
+}
+
+// TeamSetMemberRoleDTO always names the acting user, since role changes are
+// allowed to team leads only.
+type TeamSetMemberRoleDTO struct {
+	TeamName     string   `binding:"required,min=1,max=50"               json:"team_name"`
+	UserID       string   `binding:"required,min=1,max=50"               json:"user_id"`
+	Role         TeamRole `binding:"required,oneof=lead member observer" json:"role"`
+	ActingUserID string   `binding:"required,min=1,max=50"               json:"acting_user_id"`
+}
+
+type TeamMembersRemoveResultDTO struct {
+	Team         TeamDTO                      `json:"team"`
+	Reassignment ReviewsReassignmentReportDTO `json:"reassignment"`
+}
+
+type TeamRenameDTO struct {
+	TeamName    string `binding:"required,min=1,max=50" json:"team_name"`
+	NewTeamName string `binding:"required,min=1,max=50" json:"new_team_name"`
+}
+
+type TeamNameDTO struct {
+	TeamName string `binding:"required,min=1,max=50" json:"team_name"`
+}
+
+type TeamArchiveStatusDTO struct {
+	TeamName   string     `json:"team_name"`
+	Archived   bool       `json:"archived"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
+
+type TeamSetParentDTO struct {
+	TeamName       string  `binding:"required,min=1,max=50"  json:"team_name"`
+	ParentTeamName *string `binding:"omitempty,min=1,max=50" json:"parent_team_name"`
+}
+
+type TeamListQueryDTO struct {
+	Prefix          string `binding:"max=50"                  form:"prefix"`
+	Limit           int    `binding:"omitempty,min=1,max=100" form:"limit"`
+	Offset          int    `binding:"omitempty,min=0"         form:"offset"`
+	IncludeArchived bool   `form:"include_archived"`
+}
+
+type TeamSummaryDTO struct {
+	Name                 string `json:"team_name"`
+	Archived             bool   `json:"archived"`
+	MembersCount         int    `json:"members_count"`
+	ActiveMembersCount   int    `json:"active_members_count"`
+	InactiveMembersCount int    `json:"inactive_members_count"`
+}
+
+type TeamListDTO struct {
+	Teams  []TeamSummaryDTO `json:"teams"`
+	Total  int              `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+type TeamSyncDTO struct {
+	Name         string          `binding:"required,min=1,max=50" json:"team_name"`
+	Members      []TeamMemberDTO `binding:"required,dive"         json:"members"`
+	RemoveAbsent bool            `json:"remove_absent"`
+}
+
+type TeamSyncDiffDTO struct {
+	Added        []string                      `json:"added"`
+	Moved        []string                      `json:"moved"`
+	Updated      []string                      `json:"updated"`
+	Removed      []string                      `json:"removed"`
+	Reassignment *ReviewsReassignmentReportDTO `json:"reassignment,omitempty"`
+}
+
+type TeamSyncResultDTO struct {
+	Team        TeamDTO         `json:"team"`
+	TeamCreated bool            `json:"team_created"`
+	Changes     TeamSyncDiffDTO `json:"changes"`
 }
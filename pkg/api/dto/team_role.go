@@ -0,0 +1,9 @@
+package dto
+
+type TeamRole string
+
+const (
+	TeamRoleLead     TeamRole = "lead"
+	TeamRoleMember   TeamRole = "member"
+	TeamRoleObserver TeamRole = "observer"
+)
@@ -0,0 +1,18 @@
+package dto
+
+type ReviewerStrategy string
+
+const (
+	ReviewerStrategyLeastLoaded ReviewerStrategy = "LEAST_LOADED"
+	ReviewerStrategyRandom      ReviewerStrategy = "RANDOM"
+)
+
+// OwnTeamRule decides whether reviewers come from the PR team itself or from
+// its parent teams.
+type OwnTeamRule string
+
+const (
+	OwnTeamPreferred OwnTeamRule = "PREFERRED"
+	OwnTeamOnly      OwnTeamRule = "ONLY"
+	OwnTeamExcluded  OwnTeamRule = "EXCLUDED"
+)
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+)
+
+type TeamSettingsDTO struct {
+	TeamName       string           `json:"team_name"`
+	ReviewersCount int              `json:"reviewers_count"`
+	Strategy       ReviewerStrategy `json:"strategy"`
+	OwnTeamRule    OwnTeamRule      `json:"own_team_rule"`
+	UpdatedAt      *time.Time       `json:"updated_at,omitempty"`
+}
+
+// TeamSettingsUpdateDTO changes only the fields that are present.
+type TeamSettingsUpdateDTO struct {
+	TeamName       string            `binding:"required,min=1,max=50"                   json:"team_name"`
+	ReviewersCount *int              `binding:"omitempty,min=1,max=5"                   json:"reviewers_count"`
+	Strategy       *ReviewerStrategy `binding:"omitempty,oneof=LEAST_LOADED RANDOM"     json:"strategy"`
+	OwnTeamRule    *OwnTeamRule      `binding:"omitempty,oneof=PREFERRED ONLY EXCLUDED" json:"own_team_rule"`
+}
@@ -1,18 +1,155 @@
 package dto
 
+import (
+	"time"
+)
+
 type UserSetIsActiveDTO struct {
 	UserID   string `binding:"required" json:"user_id"`
 	IsActive *bool  `binding:"required" json:"is_active"`
 }
 
 type UserDTO struct {
-	ID       string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	ID           string     `json:"user_id"`
+	Username     string     `json:"username"`
+	TeamName     string     `json:"team_name"`
+	IsActive     bool       `json:"is_active"`
+	Email        *string    `json:"email,omitempty"`
+	FullName     *string    `json:"full_name,omitempty"`
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`
+}
+
+// UserUpdateDTO changes only the fields that are present. An empty email or
+// full_name clears it. The email format is checked by the service, since the
+// validator would reject the empty value.
+type UserUpdateDTO struct {
+	UserID   string  `binding:"required,min=1,max=50" json:"user_id"`
+	Username *string `binding:"omitempty,min=1"       json:"username"`
+	Email    *string `binding:"omitempty,max=255"     json:"email"`
+	FullName *string `binding:"omitempty,max=255"     json:"full_name"`
+}
+
+type UserIDDTO struct {
+	UserID string `binding:"required,min=1,max=50" json:"user_id"`
+}
+
+type UserAnonymizeResultDTO struct {
+	UserDTO
+
+	Reassignment *ReviewsReassignmentReportDTO `json:"reassignment,omitempty"`
+}
+
+type UserSetIsActiveResultDTO struct {
+	UserDTO
+
+	Reassignment *ReviewsReassignmentReportDTO `json:"reassignment,omitempty"`
+}
+
+type UserPRsQueryDTO struct {
+	UserID      string     `binding:"required,max=50"             form:"user_id"`
+	Status      PRStatus   `binding:"omitempty,oneof=OPEN MERGED" form:"status"`
+	CreatedFrom *time.Time `form:"created_from"                   time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to"                     time_format:"2006-01-02T15:04:05Z07:00"`
+	Order       string     `binding:"omitempty,oneof=asc desc"    form:"order"`
+	Cursor      string     `form:"cursor"`
+	Limit       int        `binding:"omitempty,min=1,max=100"     form:"limit"`
 }
 
 type UserPRsDTO struct {
 	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+}
+
+type UserReviewQueueQueryDTO struct {
+	UserID          string `binding:"required,max=50" form:"user_id"`
+	IncludeReviewed bool   `form:"include_reviewed"`
+}
+
+type ReviewerVerdictDTO struct {
+	UserID  string         `json:"user_id"`
+	Verdict *ReviewVerdict `json:"verdict"`
+}
+
+type ReviewQueueItemDTO struct {
+	PullRequestID   string               `json:"pull_request_id"`
+	PullRequestName string               `json:"pull_request_name"`
+	AuthorID        string               `json:"author_id"`
+	Priority        PRPriority           `json:"priority"`
+	CreatedAt       time.Time            `json:"created_at"`
+	AssignedAt      time.Time            `json:"assigned_at"`
+	Deadline        *time.Time           `json:"deadline,omitempty"`
+	Verdict         *ReviewVerdict       `json:"verdict,omitempty"`
+	OtherReviewers  []ReviewerVerdictDTO `json:"other_reviewers"`
+}
+
+type UserReviewQueueDTO struct {
+	UserID       string               `json:"user_id"`
+	PullRequests []ReviewQueueItemDTO `json:"pull_requests"`
+}
+
+type UserMoveTeamDTO struct {
+	UserID          string `binding:"required,min=1,max=50" json:"user_id"`
+	TeamName        string `binding:"required,min=1,max=50" json:"team_name"`
+	ReassignReviews bool   `json:"reassign_reviews"`
+}
+
+type UserMoveTeamResultDTO struct {
+	UserDTO
+
+	PreviousTeamName string                        `json:"previous_team_name"`
+	MovedAt          time.Time                     `json:"moved_at"`
+	Reassignment     *ReviewsReassignmentReportDTO `json:"reassignment,omitempty"`
+}
+
+type TeamMembershipChangeDTO struct {
+	FromTeamName *string   `json:"from_team_name"`
+	ToTeamName   *string   `json:"to_team_name"`
+	ChangedAt    time.Time `json:"changed_at"`
+}
+
+type UserTeamHistoryDTO struct {
+	UserID  string                    `json:"user_id"`
+	Changes []TeamMembershipChangeDTO `json:"changes"`
+}
+
+type UserTeamMembershipDTO struct {
+	UserID   string   `binding:"required,min=1,max=50"                json:"user_id"`
+	TeamName string   `binding:"required,min=1,max=50"                json:"team_name"`
+	Role     TeamRole `binding:"omitempty,oneof=lead member observer" json:"role,omitempty"`
+}
+
+type UserTeamsDTO struct {
+	UserID              string   `json:"user_id"`
+	PrimaryTeamName     string   `json:"primary_team_name"`
+	AdditionalTeamNames []string `json:"additional_team_names"`
+}
+
+// UserTeamRowDTO is one team of the user as a flat row of the CSV and NDJSON
+// exports. Primary marks the primary team, the rest are additional ones.
+type UserTeamRowDTO struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Primary  bool   `json:"primary"`
+}
+
+type UserIdentityDTO struct {
+	Provider   IdentityProvider `json:"provider"`
+	ExternalID string           `json:"external_id"`
+}
+
+type UserIdentityLinkDTO struct {
+	UserID     string           `binding:"required,min=1,max=50"                    json:"user_id"`
+	Provider   IdentityProvider `binding:"required,oneof=GITHUB GITLAB SLACK EMAIL" json:"provider"`
+	ExternalID string           `binding:"required,min=1,max=255"                   json:"external_id"`
+}
+
+type UserIdentitiesDTO struct {
+	UserID     string            `json:"user_id"`
+	Identities []UserIdentityDTO `json:"identities"`
+}
+
+type UserResolveQueryDTO struct {
+	Provider   IdentityProvider `binding:"required,oneof=GITHUB GITLAB SLACK EMAIL" form:"provider"`
+	ExternalID string           `binding:"required,max=255"                         form:"external_id"`
 }